@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+const (
+	// accountCacheTTL is how long fetched account settings are reused
+	// before /settings is asked again.
+	accountCacheTTL = 24 * time.Hour
+	// accountTimeout bounds the /settings request, so an outage delays
+	// startup by no more than this.
+	accountTimeout = 5 * time.Second
+)
+
+// offlineCommands never talk to Wallhaven, so they skip the account
+// settings lookup.
+var offlineCommands = []string{"local", "lo", "review", "set", "undo", "replay", "ctl", "credits", "reddit", "apod", "push", "pull", "sync-serve"}
+
+// applyAccountDefaults fills the search settings the config file leaves
+// unset from the Wallhaven account of the API key: purity, unless locked,
+// categories, resolutions, ratios and top_range. Flags given afterwards
+// still override them. The settings are cached for a day; when they can't
+// be fetched, the built-in defaults stand.
+func applyAccountDefaults(cfg *config.Config, verbose bool) {
+	s, err := accountSettings(cfg.APIKey)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: could not fetch account settings: %v")+"\n", err)
+		}
+		return
+	}
+	if !cfg.IsSet("purity") && !cfg.PurityLock && len(s.Purity) > 0 {
+		cfg.Purity = s.Purity
+	}
+	if !cfg.IsSet("categories") && len(s.Categories) > 0 {
+		cfg.Categories = s.Categories
+	}
+	if !cfg.IsSet("resolutions") && len(s.Resolutions) > 0 {
+		cfg.Resolutions = s.Resolutions
+	}
+	if !cfg.IsSet("ratios") && len(s.AspectRatios) > 0 {
+		cfg.Ratios = s.AspectRatios
+	}
+	if !cfg.IsSet("top_range") && s.ToplistRange != "" && api.ValidateTopRange(s.ToplistRange) == nil {
+		cfg.TopRange = s.ToplistRange
+	}
+}
+
+// accountSettings returns the settings of apiKey's account, from the cache
+// while it is fresh. A stale cache is used if the request fails.
+func accountSettings(apiKey string) (api.Settings, error) {
+	type cached struct {
+		Key      string       `json:"key"` // last characters only, to notice a change
+		Fetched  time.Time    `json:"fetched"`
+		Settings api.Settings `json:"settings"`
+	}
+	file := filepath.Join(config.CacheDir(), "account-settings.json")
+	key := apiKey[max(len(apiKey)-6, 0):]
+
+	var c cached
+	haveCache := false
+	if b, err := os.ReadFile(file); err == nil && json.Unmarshal(b, &c) == nil && c.Key == key {
+		if time.Since(c.Fetched) < accountCacheTTL {
+			return c.Settings, nil
+		}
+		haveCache = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), accountTimeout)
+	defer cancel()
+	client := &api.Client{APIKey: apiKey, UserAgent: userAgent()}
+	s, err := client.SettingsContext(ctx)
+	if err != nil {
+		if haveCache {
+			return c.Settings, nil
+		}
+		return api.Settings{}, err
+	}
+	if b, err := json.Marshal(cached{Key: key, Fetched: time.Now(), Settings: s}); err == nil {
+		os.MkdirAll(filepath.Dir(file), 0o755) //nolint:errcheck
+		os.WriteFile(file, b, 0o600)           //nolint:errcheck
+	}
+	return s, nil
+}
+
+// needsAccount reports whether cmd searches Wallhaven, and so should use
+// the account's settings.
+func needsAccount(cmd string) bool {
+	return !slices.Contains(offlineCommands, cmd)
+}
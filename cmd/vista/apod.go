@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/apod"
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runAPOD handles `vista apod [--date d | --from d [--to d] | --random n]`:
+// NASA's Astronomy Picture of the Day, today's by default, in the grid, or
+// set with --apply. Requests use nasa_apikey from the config, or NASA's
+// rate-limited demo key.
+func runAPOD(args []string, cfg *config.Config, r renderer.ImageRenderer, o ui.Options, apply string, ev *events, verbose bool) error {
+	fs := flag.NewFlagSet("apod", flag.ContinueOnError)
+	date := fs.String("date", "", "the picture of this day, YYYY-MM-DD")
+	from := fs.String("from", "", "every picture from this day, YYYY-MM-DD")
+	to := fs.String("to", "", "end of the --from range; default today")
+	random := fs.Int("random", 0, "this many random pictures, up to 100")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src := &apod.Source{
+		APIKey:    cfg.NasaKey,
+		Count:     *random,
+		UserAgent: cfg.UserAgent,
+		Retries:   cfg.Retries,
+	}
+	for _, d := range []struct {
+		flag, value string
+		into        *time.Time
+	}{{"date", *date, &src.Date}, {"from", *from, &src.From}, {"to", *to, &src.To}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, d.value)
+		if err != nil {
+			return fmt.Errorf(i18n.T("invalid --%s %q: use YYYY-MM-DD"), d.flag, d.value)
+		}
+		*d.into = t
+	}
+	if err := src.Validate(); err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Println(i18n.T("Fetching the Astronomy Picture of the Day"))
+	}
+	wallpapers, meta, err := src.Page(1)
+	if err != nil {
+		return err
+	}
+	if apply != "" {
+		headlessApply(wallpapers, apply, o, ev, verbose)
+		return nil
+	}
+	if len(wallpapers) == 0 {
+		if verbose {
+			fmt.Println(i18n.T("No pictures found; videos are skipped."))
+		}
+		return nil
+	}
+
+	// Wallhaven's details and searches don't apply to APOD pictures.
+	o.Detailer = nil
+	o.Search = nil
+	o.Meta = meta
+	grid := ui.NewGrid(wallpapers, r, src, meta.LastPage, o)
+	defer grid.Cleanup()
+	_, err = grid.Run()
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+	"golang.org/x/term"
+)
+
+// applyResult downloads and sets one of results without opening the grid,
+// returning the local path. pick is "first" or a selection strategy, see
+// pickResult. Progress is reported to ev.
+func applyResult(results []api.Wallpaper, pick string, o ui.Options, ev *events) (string, error) {
+	i, err := pickResult(results, pick, o)
+	if err != nil {
+		return "", err
+	}
+	wp := results[i]
+	ctx := context.Background()
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		ctx = wallpaper.WithProgress(ctx, ui.PrintProgress(os.Stderr, fmt.Sprintf(i18n.T("Downloading %s"), wp.ID)))
+	}
+	path, err := downloadResult(ctx, wp, o, ev)
+	if err != nil {
+		return "", err
+	}
+	return path, setResult(wp, path, o, ev)
+}
+
+// pickResult chooses the index of the result to apply: the first, or the
+// one the named selection strategy picks (see wallpaper.Strategies).
+// Random picks skip wallpapers set within the repeat window, and every
+// strategy skips those rated below o.MinRating.
+func pickResult(results []api.Wallpaper, pick string, o ui.Options) (int, error) {
+	if len(results) == 0 {
+		return -1, errors.New(i18n.T("no wallpapers match the query"))
+	}
+	if pick == "first" {
+		return 0, nil
+	}
+	if !slices.Contains(wallpaper.Strategies, pick) {
+		return -1, fmt.Errorf(i18n.T("invalid --apply %q: use first or %s"), pick, strings.Join(wallpaper.Strategies, ", "))
+	}
+	s, err := newStrategy(pick, o)
+	if err != nil {
+		return -1, err
+	}
+	var cands []wallpaper.Candidate
+	var index []int // of each candidate in results
+	for i, wp := range results {
+		var stars int
+		if o.Ratings != nil {
+			stars = o.Ratings.Get(store.RatingKey(wp)).Stars
+		}
+		if stars > 0 && stars < o.MinRating {
+			continue
+		}
+		cands = append(cands, wallpaper.Candidate{
+			Key:    wallpaper.SavedKey(wp.Path, wp.Source, wp.ID),
+			Rating: wp.Favorites,
+			Stars:  stars,
+			Colors: wp.Colors,
+		})
+		index = append(index, i)
+	}
+	if len(cands) == 0 {
+		return -1, fmt.Errorf(i18n.T("every match is rated below %d"), o.MinRating)
+	}
+	return index[s.Pick(cands)], nil
+}
+
+// newStrategy builds the named selection strategy from the history. The
+// palette strategy matches the current wallpaper's dominant colour.
+func newStrategy(name string, o ui.Options) (wallpaper.Strategy, error) {
+	var color string
+	if name == "palette" {
+		if cur := wallpaper.Current(o.CurrentFile); cur != "" {
+			if img, err := wallpaper.DecodeReduced(cur, 256); err == nil {
+				color = api.DominantColor(img)
+			}
+		}
+	}
+	return wallpaper.NewStrategy(name, o.HistoryFile, o.RepeatWindow, color)
+}
+
+func downloadResult(ctx context.Context, wp api.Wallpaper, o ui.Options, ev *events) (string, error) {
+	path, err := wallpaper.DownloadVerifiedContext(ctx, wp.Path, o.DownloadDir, wallpaper.FileName(wp.Path, wp.Source, wp.ID), wp.Checksum)
+	if err != nil {
+		return "", err
+	}
+	ev.emit(event{Event: "downloaded", ID: wp.ID, URL: wp.URL, Path: path})
+	return path, nil
+}
+
+func setResult(wp api.Wallpaper, path string, o ui.Options, ev *events) error {
+	info := wallpaper.Info{ID: wp.ID, URL: wp.URL, Author: wp.Author, AuthorURL: wp.AuthorURL, Source: wp.Source}
+	if err := wallpaper.Set(path, info, setOptions(o)); err != nil {
+		return err
+	}
+	ev.emit(event{Event: "set", ID: wp.ID, URL: wp.URL, Path: path})
+	return nil
+}
+
+// headlessApply is --apply: it sets a result and exits, reporting failure
+// through the exit status for cron jobs and key bindings.
+func headlessApply(results []api.Wallpaper, pick string, o ui.Options, ev *events, verbose bool) {
+	path, err := applyResult(results, pick, o, ev)
+	if err != nil {
+		ev.error(err)
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	if verbose {
+		fmt.Printf(i18n.T("Wallpaper set: %s")+"\n", path)
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+)
+
+// Board defaults suit a Raspberry Pi on a 1080p panel: a few API calls an
+// hour and a cache that fits comfortably on an SD card.
+const (
+	defaultBoardInterval = 5 * time.Minute
+	defaultBoardKeep     = 30
+	defaultBoardWidth    = 1920
+)
+
+// runBoard handles `vista board`: a full-screen digital photo frame in the
+// terminal, e.g. the Linux console through chafa or kitty on a Pi. Images
+// from --query rotate every --interval, optionally only during --hours.
+// Each original is scaled down to --width once and only the scaled copy
+// is kept, the newest --keep of them, which are shown when the network
+// is down. q quits and n or space skips ahead.
+func runBoard(args []string, client *api.Client, r renderer.ImageRenderer, cacheDir string, logger *log.Logger) error {
+	fs := flag.NewFlagSet("board", flag.ContinueOnError)
+	interval := fs.Duration("interval", defaultBoardInterval, "time each image is shown")
+	query := fs.String("query", "", "search query images are picked from")
+	hours := fs.String("hours", "", "only show images between these hours, e.g. 7-23")
+	keep := fs.Int("keep", defaultBoardKeep, "scaled images kept for offline rotation")
+	width := fs.Int("width", defaultBoardWidth, "pixel width images are scaled down to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return errors.New(i18n.T("--interval must be positive"))
+	}
+	if err := api.ValidateQuery(*query); err != nil {
+		return err
+	}
+	from, to, err := parseHours(*hours)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	b := &board{
+		ctx:    ctx,
+		client: client,
+		opts:   api.SearchOptions{Query: *query, Sorting: "random"},
+		dir:    cacheDir,
+		keep:   max(*keep, 1),
+		width:  max(*width, wallpaper.ThumbWidth),
+		logger: logger,
+	}
+
+	keys := make(chan byte, 1)
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		old, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(fd, old)
+		go func() {
+			buf := make([]byte, 1)
+			for {
+				if _, err := os.Stdin.Read(buf); err != nil {
+					return
+				}
+				keys <- buf[0]
+			}
+		}()
+	}
+	fmt.Print("\033[?25l")
+	defer fmt.Print("\033[?25h\033[H\033[2J")
+
+	nextCh := make(chan string, 1)
+	go b.prepare(nextCh, b.shown)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case k := <-keys:
+			switch k {
+			case 'q', 3: // q or Ctrl+C
+				return nil
+			case 'n', ' ':
+				timer.Reset(0)
+			}
+			continue
+		case <-timer.C:
+		}
+
+		if wait := untilHours(time.Now(), from, to); wait > 0 {
+			b.blank(r)
+			timer.Reset(wait)
+			continue
+		}
+		var path string
+		select {
+		case path = <-nextCh:
+		case <-ctx.Done():
+			return nil
+		}
+		if path != "" {
+			b.show(r, path)
+		}
+		go b.prepare(nextCh, b.shown)
+		timer.Reset(*interval)
+	}
+}
+
+// board is the rotation state of `vista board`.
+type board struct {
+	ctx    context.Context // cancelled on SIGINT or SIGTERM
+	client *api.Client
+	opts   api.SearchOptions
+	dir    string
+	keep   int
+	width  int
+	logger *log.Logger
+
+	queue []api.Wallpaper // the rest of the last random page
+	shown string          // scaled image on screen
+}
+
+// prepare sends the next image to show: a new one from the query, or a
+// cached one when that fails. It sends "" if there is nothing at all.
+// shown is the image on screen, which stays cached; prepare runs in the
+// background, so it gets its own copy.
+func (b *board) prepare(ch chan<- string, shown string) {
+	path, err := b.fetch(shown)
+	if err != nil {
+		if b.logger != nil {
+			b.logger.Printf("board: %v", err)
+		}
+		path = b.cached(shown)
+	}
+	ch <- path
+}
+
+// fetch downloads the next wallpaper from the query and scales it down.
+// Results come a page at a time, so the API is asked once per page.
+func (b *board) fetch(shown string) (string, error) {
+	if len(b.queue) == 0 {
+		results, _, err := b.client.SearchPageContext(b.ctx, b.opts, 1)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "", errors.New(i18n.T("no wallpapers match the query"))
+		}
+		b.queue = results
+	}
+	wp := b.queue[0]
+	b.queue = b.queue[1:]
+
+	tmp := filepath.Join(b.dir, "download")
+	orig, err := wallpaper.DownloadVerifiedContext(b.ctx, wp.Path, tmp, wallpaper.FileName(wp.Path, wp.Source, wp.ID), wp.Checksum)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	path, err := wallpaper.Scaled(orig, b.dir, b.width)
+	if err != nil {
+		return "", err
+	}
+	b.prune(shown)
+	return path, nil
+}
+
+// cached returns a random scaled image other than the one on screen, or
+// "" when the cache is empty.
+func (b *board) cached(shown string) string {
+	paths := b.scaled()
+	for i, p := range paths {
+		if p == shown && len(paths) > 1 {
+			paths = append(paths[:i], paths[i+1:]...)
+			break
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[rand.IntN(len(paths))]
+}
+
+// prune deletes all but the newest keep scaled images.
+func (b *board) prune(shown string) {
+	paths := b.scaled()
+	if len(paths) <= b.keep {
+		return
+	}
+	mtime := func(p string) time.Time {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return time.Time{}
+		}
+		return fi.ModTime()
+	}
+	sort.Slice(paths, func(i, j int) bool { return mtime(paths[i]).After(mtime(paths[j])) })
+	for _, p := range paths[b.keep:] {
+		if p != shown {
+			os.Remove(p)
+		}
+	}
+}
+
+func (b *board) scaled() []string {
+	paths, _ := filepath.Glob(filepath.Join(b.dir, "*.jpg"))
+	return paths
+}
+
+// show draws path across the whole terminal.
+func (b *board) show(r renderer.ImageRenderer, path string) {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		w, h = 80, 24
+	}
+	b.clear(r)
+	b.shown = path
+	if p, ok := r.(renderer.Placer); ok {
+		p.Place("board", path, 0, 0, w, h) //nolint:errcheck
+		return
+	}
+	out, err := r.Render(path, w, h)
+	if err != nil {
+		if b.logger != nil {
+			b.logger.Printf("board: render %s: %v", path, err)
+		}
+		return
+	}
+	var s strings.Builder
+	for i, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		fmt.Fprintf(&s, "\033[%d;1H%s", i+1, line)
+	}
+	fmt.Print(s.String())
+}
+
+// blank clears the screen outside --hours.
+func (b *board) blank(r renderer.ImageRenderer) {
+	b.clear(r)
+	b.shown = ""
+}
+
+func (b *board) clear(r renderer.ImageRenderer) {
+	if p, ok := r.(renderer.Placer); ok {
+		p.Clear() //nolint:errcheck
+	}
+	seq := "\033[H\033[2J"
+	if c, ok := r.(renderer.Clearer); ok {
+		seq = c.ClearSequence() + seq
+	}
+	fmt.Print(seq)
+}
+
+// parseHours parses --hours "from-to" in whole hours, e.g. "7-23". An
+// empty value means all day.
+func parseHours(s string) (from, to int, err error) {
+	if s == "" {
+		return 0, 24, nil
+	}
+	a, z, ok := strings.Cut(s, "-")
+	from, err1 := strconv.Atoi(strings.TrimSpace(a))
+	to, err2 := strconv.Atoi(strings.TrimSpace(z))
+	if !ok || err1 != nil || err2 != nil || from < 0 || from > 23 || to < 0 || to > 24 || from == to {
+		return 0, 0, fmt.Errorf(i18n.T("invalid --hours %q: use from-to, e.g. 7-23"), s)
+	}
+	return from, to, nil
+}
+
+// untilHours returns how long from now until the display window opens, or
+// zero when now is inside it. A window like 22-6 runs past midnight.
+func untilHours(now time.Time, from, to int) time.Duration {
+	h := now.Hour()
+	inside := from <= h && h < to
+	if from > to {
+		inside = h >= from || h < to
+	}
+	if inside {
+		return 0
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), from, 0, 0, 0, now.Location())
+	if !start.After(now) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start.Sub(now)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+// runCredits handles `vista credits`: it prints the attribution for the
+// current wallpaper. Credits recorded when the wallpaper was set are used
+// as is; Wallhaven downloads fall back to the uploader from the API.
+func runCredits(currentFile string, client *api.Client) error {
+	path := wallpaper.Current(currentFile)
+	if path == "" {
+		return errors.New(i18n.T("no current wallpaper recorded"))
+	}
+	fmt.Println(path)
+
+	if c, ok := wallpaper.ReadCredit(path); ok {
+		printCredit(c)
+		return nil
+	}
+	if m := wallhavenFileID.FindStringSubmatch(strings.ToLower(filepath.Base(path))); m != nil {
+		d, err := client.GetWallpaper(m[1])
+		if err != nil {
+			return err
+		}
+		printCredit(wallpaper.Credit{Author: d.Uploader, URL: d.URL, Source: "Wallhaven"})
+		return nil
+	}
+	fmt.Println(i18n.T("No attribution recorded for this wallpaper"))
+	return nil
+}
+
+func printCredit(c wallpaper.Credit) {
+	fmt.Printf("%-10s %s\n", i18n.T("Author"), c.Author)
+	if c.AuthorURL != "" {
+		fmt.Printf("%-10s %s\n", i18n.T("Profile"), c.AuthorURL)
+	}
+	if c.Source != "" {
+		fmt.Printf("%-10s %s\n", i18n.T("Source"), c.Source)
+	}
+	if c.URL != "" {
+		fmt.Printf("%-10s %s\n", "URL", c.URL)
+	}
+}
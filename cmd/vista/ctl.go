@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+// ctlTimeout bounds a `vista ctl` round trip. A change without a prefetched
+// candidate has to search and download first.
+const ctlTimeout = 2 * time.Minute
+
+// ctlRequest is one command received on the daemon's control socket. The
+// daemon answers with a single line: "ok <detail>" or "error <message>".
+type ctlRequest struct {
+	cmd   string
+	reply chan<- string
+}
+
+// listenCtl opens the daemon's control socket at path and forwards each
+// request to ch. A socket left behind by a daemon that died is replaced;
+// one that still answers means a daemon is already running.
+func listenCtl(path string, ch chan<- ctlRequest) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf(i18n.T("a daemon is already running (%s)"), path)
+	}
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return // listener closed
+			}
+			go serveCtl(conn, ch)
+		}
+	}()
+	return ln, nil
+}
+
+func serveCtl(conn net.Conn, ch chan<- ctlRequest) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ctlTimeout)) //nolint:errcheck
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	reply := make(chan string, 1)
+	ch <- ctlRequest{cmd: strings.TrimSpace(line), reply: reply}
+	fmt.Fprintln(conn, <-reply)
+}
+
+// runCtl handles `vista ctl <command>`, sending command to the running
+// daemon and printing its answer. Only "next" is understood so far.
+func runCtl(args []string) error {
+	if len(args) != 1 {
+		return errors.New(i18n.T("usage: vista ctl next"))
+	}
+	conn, err := net.Dial("unix", config.SocketPath())
+	if err != nil {
+		return errors.New(i18n.T("no daemon is running; start one with vista daemon"))
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ctlTimeout)) //nolint:errcheck
+
+	fmt.Fprintln(conn, args[0])
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	status, detail, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if status != "ok" {
+		return errors.New(detail)
+	}
+	if detail != "" {
+		fmt.Println(detail)
+	}
+	return nil
+}
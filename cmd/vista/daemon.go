@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// daemonJitter is the fraction by which each daemon interval is randomly
+// lengthened or shortened, so several machines started together drift
+// apart instead of hitting the API in lockstep.
+const daemonJitter = 0.1
+
+// daemonRetry is how long the daemon waits after a failed change before
+// trying again, when that is shorter than the interval.
+const daemonRetry = time.Minute
+
+// runDaemon handles `vista daemon [--interval 30m] [--query q]`: it sets a
+// matching wallpaper chosen by --strategy (the config's strategy, random by
+// default), then keeps replacing it on a jittered interval until
+// interrupted. After each change the next candidate is picked and
+// downloaded ahead of time, so `vista ctl next` applies instantly. It stays
+// in the foreground; run it with & or from a service manager to keep it in
+// the background. When a search fails, fb, if set, supplies the wallpapers
+// instead.
+func runDaemon(args []string, client *api.Client, o ui.Options, strategy string, fb *fallback, ev *events, verbose bool) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	interval := fs.Duration("interval", defaultPlayInterval, "time between wallpaper changes")
+	query := fs.String("query", "", "search query wallpapers are picked from")
+	pick := fs.String("strategy", strategy, "how each wallpaper is chosen: "+strings.Join(wallpaper.Strategies, ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return errors.New(i18n.T("--interval must be positive"))
+	}
+	if err := api.ValidateQuery(*query); err != nil {
+		return err
+	}
+	if !slices.Contains(wallpaper.Strategies, *pick) {
+		return fmt.Errorf(i18n.T("unknown --strategy %q: use %s"), *pick, strings.Join(wallpaper.Strategies, ", "))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctlCh := make(chan ctlRequest)
+	ln, err := listenCtl(config.SocketPath(), ctlCh)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	d := &daemon{
+		ctx:        ctx,
+		client:     client,
+		opts:       api.SearchOptions{Query: *query, Sorting: "random"},
+		o:          o,
+		strategy:   *pick,
+		fallback:   fb,
+		ev:         ev,
+		prefetchCh: make(chan prefetched, 1),
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		var reply chan<- string
+		select {
+		case <-ctx.Done():
+			if verbose {
+				fmt.Println(i18n.T("Daemon stopped"))
+			}
+			return nil
+		case p := <-d.prefetchCh:
+			if p.gen == d.gen {
+				d.next = p.wp
+			}
+			continue
+		case req := <-ctlCh:
+			if req.cmd != "next" {
+				req.reply <- "error " + fmt.Sprintf(i18n.T("unknown daemon command %q"), req.cmd)
+				continue
+			}
+			reply = req.reply
+		case <-timer.C:
+		}
+
+		wait := jittered(*interval)
+		path, err := d.change()
+		if err != nil {
+			ev.error(err)
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			wait = min(wait, daemonRetry)
+			if reply != nil {
+				reply <- "error " + err.Error()
+			}
+		} else {
+			if verbose {
+				fmt.Printf(i18n.T("Set %s, next change in %s")+"\n", path, wait.Round(time.Second))
+			}
+			if reply != nil {
+				reply <- "ok " + path
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// daemon is the rotation state of `vista daemon`.
+type daemon struct {
+	ctx    context.Context // cancelled on SIGINT or SIGTERM
+	client *api.Client
+	opts   api.SearchOptions
+	o      ui.Options
+	ev     *events
+	// strategy picks each wallpaper; see wallpaper.Strategies.
+	strategy string
+	fallback *fallback // used when a search fails; nil for none
+
+	// next is the downloaded candidate for the following change, nil until
+	// a prefetch finishes. gen discards prefetches started before the
+	// latest change.
+	next       *api.Wallpaper
+	gen        int
+	prefetchCh chan prefetched
+}
+
+type prefetched struct {
+	gen int
+	wp  *api.Wallpaper // Path is the downloaded file
+}
+
+// change sets the prefetched candidate if it is still usable, otherwise a
+// live pick, then starts prefetching the one after.
+func (d *daemon) change() (string, error) {
+	wp, path := d.takeNext()
+	if path == "" {
+		d.ev.emit(event{Event: "search_started", Query: d.opts.Query, Sorting: d.opts.Sorting})
+		results, _, err := d.client.SearchPageContext(d.ctx, d.opts, 1)
+		if err != nil && d.ctx.Err() == nil {
+			results, err = d.fallback.results(err)
+		}
+		if err != nil {
+			return "", err
+		}
+		i, err := pickResult(results, d.strategy, d.o)
+		if err != nil {
+			return "", err
+		}
+		wp = results[i]
+		if path, err = downloadResult(d.ctx, wp, d.o, d.ev); err != nil {
+			return "", err
+		}
+	}
+	if err := setResult(wp, path, d.o, d.ev); err != nil {
+		return "", err
+	}
+
+	d.gen++
+	go d.prefetch(d.gen)
+	return path, nil
+}
+
+// takeNext returns the prefetched candidate and its file, or an empty path
+// when there is none or it was invalidated: deleted or corrupted on disk,
+// or set by something else since it was picked.
+func (d *daemon) takeNext() (api.Wallpaper, string) {
+	next := d.next
+	d.next = nil
+	if next == nil || wallpaper.Validate(next.Path) != nil {
+		return api.Wallpaper{}, ""
+	}
+	key := wallpaper.Key(next.Path)
+	for _, k := range wallpaper.RecentKeys(d.o.HistoryFile, d.o.RepeatWindow) {
+		if k == key {
+			return api.Wallpaper{}, ""
+		}
+	}
+	wp := *next
+	return wp, wp.Path
+}
+
+// prefetch picks and downloads the next candidate in the background.
+// Failures are only logged; the next change then picks live.
+func (d *daemon) prefetch(gen int) {
+	results, _, err := d.client.SearchPageContext(d.ctx, d.opts, 1)
+	if err == nil {
+		var i int
+		if i, err = pickResult(results, d.strategy, d.o); err == nil {
+			wp := results[i]
+			var path string
+			if path, err = wallpaper.DownloadVerifiedContext(d.ctx, wp.Path, d.o.DownloadDir, wallpaper.FileName(wp.Path, wp.Source, wp.ID), wp.Checksum); err == nil {
+				wp.Path = path
+				d.prefetchCh <- prefetched{gen: gen, wp: &wp}
+				return
+			}
+		}
+	}
+	if d.o.Logger != nil {
+		d.o.Logger.Printf("daemon prefetch: %v", err)
+	}
+}
+
+// jittered returns d shifted randomly by up to daemonJitter of itself.
+func jittered(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*2-1)*daemonJitter*float64(d))
+}
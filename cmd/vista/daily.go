@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// dailyPick is the wallpaper of the day as cached in daily.json.
+type dailyPick struct {
+	Day       string        `json:"day"` // local date, YYYY-MM-DD
+	Key       string        `json:"key"` // searchKey of the query it was picked from
+	Wallpaper api.Wallpaper `json:"wallpaper"`
+}
+
+// runDaily handles `vista daily [--query q]`: it sets the wallpaper of the
+// day. The pick is the first result of a random search seeded by the date,
+// so it stays the same all day, and it is cached: later runs that day set
+// the same file without searching, and do nothing at all while it is still
+// the current wallpaper. That makes it safe to run from a login script.
+func runDaily(args []string, client *api.Client, o ui.Options, ev *events, verbose bool) error {
+	fs := flag.NewFlagSet("daily", flag.ContinueOnError)
+	query := fs.String("query", "", "search query the wallpaper is picked from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := api.ValidateQuery(*query); err != nil {
+		return err
+	}
+
+	day := time.Now().Format(time.DateOnly)
+	key := searchKey(client, api.SearchOptions{Query: *query})
+	file := filepath.Join(config.CacheDir(), "daily.json")
+
+	var c dailyPick
+	if b, err := os.ReadFile(file); err == nil && json.Unmarshal(b, &c) == nil &&
+		c.Day == day && c.Key == key && wallpaper.Validate(c.Wallpaper.Path) == nil {
+		if wallpaper.Current(o.CurrentFile) == c.Wallpaper.Path {
+			if verbose {
+				fmt.Printf(i18n.T("Already set today: %s")+"\n", c.Wallpaper.Path)
+			}
+			return nil
+		}
+		return setDaily(c.Wallpaper, o, ev, verbose)
+	}
+
+	opts := api.SearchOptions{Query: *query, Sorting: "random", Seed: dailySeed(day, key)}
+	ev.emit(event{Event: "search_started", Query: opts.Query, Sorting: opts.Sorting})
+	results, _, err := client.SearchPage(opts, 1)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return errors.New(i18n.T("no wallpapers match the query"))
+	}
+	wp := results[0]
+	if wp.Path, err = downloadResult(context.Background(), wp, o, ev); err != nil {
+		return err
+	}
+	if b, err := json.Marshal(dailyPick{Day: day, Key: key, Wallpaper: wp}); err == nil {
+		os.MkdirAll(filepath.Dir(file), 0o755) //nolint:errcheck
+		os.WriteFile(file, b, 0o644)           //nolint:errcheck
+	}
+	return setDaily(wp, o, ev, verbose)
+}
+
+// setDaily sets the day's downloaded wallpaper.
+func setDaily(wp api.Wallpaper, o ui.Options, ev *events, verbose bool) error {
+	if err := setResult(wp, wp.Path, o, ev); err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Printf(i18n.T("Wallpaper of the day: %s")+"\n", wp.Path)
+	}
+	return nil
+}
+
+// dailySeed derives Wallhaven's six character random seed from the day and
+// the search, so the same search gives the same order all day.
+func dailySeed(day, key string) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	sum := sha256.Sum256([]byte(day + "\n" + key))
+	seed := make([]byte, 6)
+	for i := range seed {
+		seed[i] = alphabet[int(sum[i])%len(alphabet)]
+	}
+	return string(seed)
+}
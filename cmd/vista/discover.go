@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+// starter is one of the curated searches `vista discover` offers.
+type starter struct {
+	name       string
+	desc       string
+	query      string
+	categories string // Wallhaven category bits; empty keeps the configured ones
+}
+
+// starters are the searches `vista discover` offers, each sorted by
+// toplist so a first look shows the best of it.
+var starters = []starter{
+	{"minimal", "clean, simple shapes and flat colour", "minimalism", ""},
+	{"nature", "landscapes, forests, mountains and water", "nature", "100"},
+	{"space", "planets, nebulae and starfields", "space", ""},
+	{"anime", "anime and illustration", "", "010"},
+	{"dark", "dark, low-light wallpapers", "dark", ""},
+	{"city", "cityscapes and architecture", "city", ""},
+	{"abstract", "abstract art and patterns", "abstract", ""},
+}
+
+// discoverSearch handles `vista discover [name]`: it shows the starters as a
+// numbered menu, or takes one by name, and returns its search. The chosen
+// starter's categories override the client's.
+func discoverSearch(args []string, client *api.Client) (api.SearchOptions, string, error) {
+	var s starter
+	switch {
+	case len(args) > 0:
+		var ok bool
+		if s, ok = findStarter(args[0]); !ok {
+			return api.SearchOptions{}, "", fmt.Errorf(i18n.T("unknown collection %q: use %s"), args[0], strings.Join(starterNames(), ", "))
+		}
+	default:
+		fmt.Println(i18n.T("Pick a starting point:"))
+		for i, s := range starters {
+			fmt.Printf("  %d  %-10s %s\n", i+1, s.name, i18n.T(s.desc))
+		}
+		fmt.Printf(i18n.T("Number or name [1-%d]: "), len(starters))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return api.SearchOptions{}, "", nil
+		}
+		n, err := strconv.Atoi(answer)
+		ok := err == nil && n >= 1 && n <= len(starters)
+		if ok {
+			s = starters[n-1]
+		} else if s, ok = findStarter(answer); !ok {
+			return api.SearchOptions{}, "", fmt.Errorf(i18n.T("unknown collection %q: use %s"), answer, strings.Join(starterNames(), ", "))
+		}
+	}
+	if s.categories != "" {
+		client.Categories = s.categories
+	}
+	return api.SearchOptions{Query: s.query, Sorting: "toplist"}, fmt.Sprintf(i18n.T("Fetching %s wallpapers"), s.name), nil
+}
+
+func findStarter(name string) (starter, bool) {
+	for _, s := range starters {
+		if strings.EqualFold(s.name, name) {
+			return s, true
+		}
+	}
+	return starter{}, false
+}
+
+func starterNames() []string {
+	names := make([]string, len(starters))
+	for i, s := range starters {
+		names[i] = s.name
+	}
+	return names
+}
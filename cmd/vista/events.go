@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+// event is one line of --events json output.
+type event struct {
+	Event   string    `json:"event"` // search_started, fallback, downloaded, set or error
+	Time    time.Time `json:"time"`
+	Query   string    `json:"query,omitempty"`
+	Sorting string    `json:"sorting,omitempty"`
+	ID      string    `json:"id,omitempty"`
+	URL     string    `json:"url,omitempty"`
+	Path    string    `json:"path,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// events writes newline-delimited JSON events to stdout for wrappers that
+// script around the daemon and --apply. A nil *events discards everything,
+// so callers emit unconditionally.
+type events struct {
+	enc *json.Encoder
+}
+
+// newEvents returns the emitter for the --events format, or nil when
+// events are off.
+func newEvents(format string) (*events, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "json":
+		return &events{enc: json.NewEncoder(os.Stdout)}, nil
+	}
+	return nil, fmt.Errorf(i18n.T("invalid --events %q: use json"), format)
+}
+
+func (e *events) emit(ev event) {
+	if e == nil {
+		return
+	}
+	ev.Time = time.Now()
+	e.enc.Encode(ev)
+}
+
+func (e *events) error(err error) {
+	e.emit(event{Event: "error", Error: err.Error()})
+}
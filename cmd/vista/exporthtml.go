@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"mime"
+	"os"
+	"path/filepath"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// galleryItem is one cell of the exported gallery.
+type galleryItem struct {
+	Wallpaper api.Wallpaper
+	Thumb     template.URL // data: URI, so the page is a single file
+}
+
+var galleryTemplate = template.Must(template.New("gallery").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; padding: 1.5rem; background: #111; color: #ccc; font: 14px sans-serif; }
+h1 { font-weight: normal; color: #eee; }
+.grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(300px, 1fr)); gap: 1rem; }
+figure { margin: 0; }
+img { width: 100%; aspect-ratio: 3 / 2; object-fit: cover; border-radius: 4px; display: block; }
+figcaption { margin-top: .3rem; display: flex; justify-content: space-between; }
+a { color: #8ab4f8; text-decoration: none; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="grid">
+{{- range .Items}}
+<figure>
+<a href="{{.Wallpaper.Path}}"><img src="{{.Thumb}}" alt="{{.Wallpaper.ID}}" loading="lazy"></a>
+<figcaption>
+{{- if .Wallpaper.URL}}<a href="{{.Wallpaper.URL}}">{{.Wallpaper.ID}}</a>{{else}}<span>{{.Wallpaper.ID}}</span>{{end -}}
+<span>{{.Wallpaper.Resolution}}</span>
+</figcaption>
+</figure>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// runExportHTML handles `vista export-html <query> [--out file] [-n N]`: it
+// writes a static gallery of the first N results, each thumbnail linking to
+// the original, to share a search with someone who doesn't use a terminal.
+// Thumbnails come from thumbDir, downloading only those not cached yet, and
+// are embedded in the page. Flags may come before or after the query.
+func runExportHTML(args []string, client *api.Client, thumbDir string, o ui.Options, verbose bool) error {
+	fs := flag.NewFlagSet("export-html", flag.ContinueOnError)
+	out := fs.String("out", "gallery.html", "file to write the gallery to")
+	count := fs.Int("n", 24, "number of wallpapers in the gallery")
+	sorting := fs.String("sorting", "relevance", "search sorting, e.g. toplist or random")
+	title := fs.String("title", "", "page heading; defaults to the query")
+	query, err := parseQueryFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *count < 1 {
+		return errors.New(i18n.T("-n must be at least 1"))
+	}
+
+	results, err := searchResults(client, query, *sorting, *count)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, i18n.T("Fetching %d thumbnails...")+"\n", len(results))
+	}
+	urls := make([]string, len(results))
+	for i, wp := range results {
+		urls[i] = wp.Thumbs.Small
+	}
+	var items []galleryItem
+	for i, p := range wallpaper.DownloadAll(urls, thumbDir, o.DownloadWorkers) {
+		if p == "" {
+			continue
+		}
+		uri, err := dataURI(p)
+		if err != nil {
+			continue
+		}
+		items = append(items, galleryItem{Wallpaper: results[i], Thumb: uri})
+	}
+	if len(items) == 0 {
+		return errors.New(i18n.T("no thumbnails could be downloaded"))
+	}
+	if verbose && len(items) < len(results) {
+		fmt.Fprintf(os.Stderr, i18n.T("%d thumbnails failed, leaving them out")+"\n", len(results)-len(items))
+	}
+
+	heading := *title
+	if heading == "" {
+		heading = query
+	}
+	if heading == "" {
+		heading = "vista"
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := galleryTemplate.Execute(f, struct {
+		Title string
+		Items []galleryItem
+	}{heading, items}); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, i18n.T("Wrote %d wallpapers to %s")+"\n", len(items), *out)
+	}
+	return nil
+}
+
+// dataURI returns the image at path as a data: URI.
+func dataURI(path string) (template.URL, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	typ := mime.TypeByExtension(filepath.Ext(path))
+	if typ == "" {
+		typ = "image/jpeg"
+	}
+	return template.URL("data:" + typ + ";base64," + base64.StdEncoding.EncodeToString(b)), nil
+}
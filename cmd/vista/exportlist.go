@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// exportFormats are the --format values of `vista export-list`.
+var exportFormats = []string{"paths", "m3u", "feh", "swww", "swaybg"}
+
+// runExportList handles `vista export-list <query> [--format f] [-n N]`: it
+// downloads the first N results of a search and prints them for another
+// tool to display, either as a plain path list or m3u playlist, or as a
+// shell script that rotates them with feh, swww or swaybg every --interval.
+// Flags may come before or after the query.
+func runExportList(args []string, client *api.Client, o ui.Options, verbose bool) error {
+	fs := flag.NewFlagSet("export-list", flag.ContinueOnError)
+	format := fs.String("format", "paths", "output format: "+strings.Join(exportFormats, ", "))
+	count := fs.Int("n", 24, "number of wallpapers to download")
+	sorting := fs.String("sorting", "relevance", "search sorting, e.g. toplist or random")
+	interval := fs.Duration("interval", defaultPlayInterval, "time between changes in rotation scripts")
+	out := fs.String("o", "", "write to this file instead of standard output")
+	query, err := parseQueryFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(exportFormats, *format) {
+		return fmt.Errorf(i18n.T("unknown --format %q: use %s"), *format, strings.Join(exportFormats, ", "))
+	}
+	if *count < 1 {
+		return errors.New(i18n.T("-n must be at least 1"))
+	}
+	if *interval <= 0 {
+		return errors.New(i18n.T("--interval must be positive"))
+	}
+	results, err := searchResults(client, query, *sorting, *count)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, i18n.T("Downloading %d wallpapers to %s...")+"\n", len(results), o.DownloadDir)
+	}
+	urls := make([]string, len(results))
+	for i, wp := range results {
+		urls[i] = wp.Path
+	}
+	var paths []string
+	for _, p := range wallpaper.DownloadAll(urls, o.DownloadDir, o.DownloadWorkers) {
+		if p == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		paths = append(paths, p)
+	}
+	if len(paths) == 0 {
+		return errors.New(i18n.T("no wallpapers could be downloaded"))
+	}
+	if verbose && len(paths) < len(results) {
+		fmt.Fprintf(os.Stderr, i18n.T("%d downloads failed")+"\n", len(results)-len(paths))
+	}
+
+	if *out == "" {
+		return writeExport(os.Stdout, *format, paths, *interval)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writeExport(f, *format, paths, *interval); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if *format != "paths" && *format != "m3u" {
+		return os.Chmod(*out, 0o755)
+	}
+	return nil
+}
+
+// parseQueryFlags parses args into fs, allowing flags before, after and
+// between the words of the query, which it returns.
+func parseQueryFlags(fs *flag.FlagSet, args []string) (string, error) {
+	var words []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return "", err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		words = append(words, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// searchResults returns the first count results of a search, reading as
+// many pages as that takes.
+func searchResults(client *api.Client, query, sorting string, count int) ([]api.Wallpaper, error) {
+	if err := api.ValidateQuery(query); err != nil {
+		return nil, err
+	}
+	// api.Search carries the seed of a random search from page to page.
+	search := &api.Search{Client: client, Opts: api.SearchOptions{Query: query, Sorting: sorting}}
+	var results []api.Wallpaper
+	for page := 1; len(results) < count; page++ {
+		batch, meta, err := search.Page(page)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+		if len(batch) == 0 || page >= meta.LastPage {
+			break
+		}
+	}
+	if len(results) == 0 {
+		return nil, errors.New(i18n.T("no wallpapers match the query"))
+	}
+	return results[:min(len(results), count)], nil
+}
+
+// writeExport writes paths in format. Scripts show the wallpapers in
+// search order and start over after the last one.
+func writeExport(w io.Writer, format string, paths []string, interval time.Duration) error {
+	var b strings.Builder
+	switch format {
+	case "paths":
+		for _, p := range paths {
+			fmt.Fprintln(&b, p)
+		}
+	case "m3u":
+		fmt.Fprintln(&b, "#EXTM3U")
+		for _, p := range paths {
+			fmt.Fprintln(&b, p)
+		}
+	default:
+		var set string
+		switch format {
+		case "feh":
+			set = `feh --no-fehbg --bg-fill "$f"`
+		case "swww":
+			set = `swww img "$f"`
+		case "swaybg":
+			// swaybg keeps running while its wallpaper is shown, so the
+			// previous one is stopped once the next is up.
+			set = `swaybg -m fill -i "$f" & new=$!; [ -n "$pid" ] && kill "$pid"; pid=$new`
+		}
+		fmt.Fprintln(&b, "#!/bin/sh")
+		fmt.Fprintln(&b, "# Generated by vista export-list.")
+		fmt.Fprintln(&b, "set -- \\")
+		for _, p := range paths {
+			fmt.Fprintf(&b, "\t%s \\\n", shellQuote(p))
+		}
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "while :; do")
+		fmt.Fprintln(&b, `	for f in "$@"; do`)
+		fmt.Fprintf(&b, "\t\t%s\n", set)
+		fmt.Fprintf(&b, "\t\tsleep %d\n", int(interval.Seconds()))
+		fmt.Fprintln(&b, "\tdone")
+		fmt.Fprintln(&b, "done")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+)
+
+// fallbackModes are the accepted values of the fallback config option.
+var fallbackModes = []string{"none", "local"}
+
+// fallback supplies wallpapers when a Wallhaven search fails even after
+// the client's retries, so --apply and the daemon keep changing the
+// wallpaper through an outage instead of silently stopping. A nil
+// *fallback passes the error on.
+type fallback struct {
+	downloadDir string
+	library     *store.Library
+	ev          *events
+}
+
+// newFallback returns the fallback for mode, or nil for none.
+func newFallback(mode, downloadDir string, lib *store.Library, ev *events) *fallback {
+	if mode != "local" {
+		return nil
+	}
+	return &fallback{downloadDir: downloadDir, library: lib, ev: ev}
+}
+
+// results returns the downloaded and imported wallpapers in place of a
+// search that failed with err, logging the failover. It returns err when
+// there is no fallback or nothing to fall back to.
+func (f *fallback) results(err error) ([]api.Wallpaper, error) {
+	if f == nil {
+		return nil, err
+	}
+	wallpapers, _ := localWallpapers(f.downloadDir)
+	wallpapers = append(wallpapers, libraryWallpapers(f.library, f.downloadDir)...)
+	if len(wallpapers) == 0 {
+		return nil, err
+	}
+	f.ev.emit(event{Event: "fallback", Error: err.Error()})
+	fmt.Fprintf(os.Stderr, i18n.T("Warning: search failed (%v), using the local library")+"\n", err)
+	return wallpapers, nil
+}
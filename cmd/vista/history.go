@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+// runHistoryList handles `vista history list [--since date]`: every
+// wallpaper change, newest first.
+func runHistoryList(args []string, file string) error {
+	fs := flag.NewFlagSet("history list", flag.ContinueOnError)
+	since := fs.String("since", "", "only show changes on or after this date (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var from time.Time
+	if *since != "" {
+		var err error
+		if from, err = time.ParseInLocation(time.DateOnly, *since, time.Local); err != nil {
+			return fmt.Errorf(i18n.T("invalid --since %q: use YYYY-MM-DD"), *since)
+		}
+	}
+
+	entries, err := wallpaper.ReadHistory(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.New(i18n.T("no wallpaper has been set yet"))
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Time.Before(from) {
+			break
+		}
+		id := e.ID
+		if id == "" {
+			id = "-"
+		}
+		fmt.Printf("%s  %-8s %s", e.Time.Local().Format("2006-01-02 15:04"), id, e.Path)
+		if e.URL != "" {
+			fmt.Printf("  %s", e.URL)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// historyWallpapers returns each wallpaper in the history file once, most
+// recently set first. Files deleted since are left out.
+func historyWallpapers(file string) ([]api.Wallpaper, error) {
+	entries, err := wallpaper.ReadHistory(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var wallpapers []api.Wallpaper
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if seen[e.Path] {
+			continue
+		}
+		seen[e.Path] = true
+		if _, err := os.Stat(e.Path); err != nil {
+			continue
+		}
+		id := e.ID
+		if id == "" {
+			id = filepath.Base(e.Path)
+		}
+		wallpapers = append(wallpapers, api.Wallpaper{
+			ID:     id,
+			URL:    e.URL,
+			Path:   e.Path,
+			Thumbs: api.Thumbs{Small: e.Path},
+		})
+	}
+	return wallpapers, nil
+}
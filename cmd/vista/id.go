@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+// wallhavenID matches a bare Wallhaven wallpaper ID.
+var wallhavenID = regexp.MustCompile(`^[0-9a-z]+$`)
+
+// parseWallpaperID returns the wallpaper ID in arg: a bare ID, a page URL
+// (wallhaven.cc/w/8xkxjo), a short link (whvn.cc/8xkxjo) or an image or
+// thumbnail URL (w.wallhaven.cc/full/8x/wallhaven-8xkxjo.jpg).
+func parseWallpaperID(arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+	if wallhavenID.MatchString(arg) {
+		return arg, nil
+	}
+	s := arg
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err == nil && (u.Hostname() == "whvn.cc" || strings.HasSuffix(u.Hostname(), "wallhaven.cc")) {
+		name := path.Base(u.Path)
+		name = strings.TrimSuffix(strings.TrimPrefix(name, "wallhaven-"), path.Ext(name))
+		if wallhavenID.MatchString(name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf(i18n.T("not a Wallhaven ID or URL: %q"), arg)
+}
+
+// lookupID handles `vista id <id|url>`: it fetches the one wallpaper, ready
+// for the grid to open on its detail screen.
+func lookupID(args []string, client *api.Client) (api.Wallpaper, error) {
+	if len(args) != 1 {
+		return api.Wallpaper{}, errors.New(i18n.T("id needs exactly one wallpaper ID or URL"))
+	}
+	id, err := parseWallpaperID(args[0])
+	if err != nil {
+		return api.Wallpaper{}, err
+	}
+	d, err := client.GetWallpaper(id)
+	if err != nil {
+		return api.Wallpaper{}, err
+	}
+	return d.Wallpaper, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+// runLibrary handles `vista library import [--lookup] <dir>`,
+// `vista library list [--sort rating] [--min-rating n]` and
+// `vista library rate <id|file> <0-5> [note]`.
+func runLibrary(args []string, lib *store.Library, ratings *store.Ratings, client *api.Client, downloadDir string, verbose bool) error {
+	if len(args) == 0 {
+		return errors.New(i18n.T("library needs a subcommand: import, list or rate"))
+	}
+	switch args[0] {
+	case "import":
+		fs := flag.NewFlagSet("library import", flag.ContinueOnError)
+		lookup := fs.Bool("lookup", false, "confirm Wallhaven IDs found in file names against the API")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return errors.New(i18n.T("library import needs a directory"))
+		}
+		res, err := lib.Import(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Printf(i18n.T("Added %d, unchanged %d, %d duplicates")+"\n", res.Added, res.Unchanged, len(res.Duplicates))
+		if verbose {
+			for _, d := range res.Duplicates {
+				fmt.Printf("  %s %s\n", i18n.T("duplicate:"), d)
+			}
+		}
+		if *lookup {
+			return lookupLibrary(lib, client, verbose)
+		}
+		return nil
+
+	case "list":
+		fs := flag.NewFlagSet("library list", flag.ContinueOnError)
+		sortBy := fs.String("sort", "", "order by rating, highest first")
+		minRating := fs.Int("min-rating", 0, "only list wallpapers rated at least this")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *sortBy != "" && *sortBy != "rating" {
+			return fmt.Errorf(i18n.T("unknown sort %q: use rating"), *sortBy)
+		}
+		var entries []store.LibraryEntry
+		for _, e := range lib.Entries() {
+			if ratings.Get(wallpaper.Key(e.Path)).Stars >= *minRating {
+				entries = append(entries, e)
+			}
+		}
+		if *sortBy == "rating" {
+			slices.SortStableFunc(entries, func(a, b store.LibraryEntry) int {
+				return ratings.Get(wallpaper.Key(b.Path)).Stars - ratings.Get(wallpaper.Key(a.Path)).Stars
+			})
+		}
+		for _, e := range entries {
+			r := ratings.Get(wallpaper.Key(e.Path))
+			stars := "-"
+			if r.Stars > 0 {
+				stars = fmt.Sprintf("%d/%d", r.Stars, store.MaxStars)
+			}
+			line := fmt.Sprintf("%-10s %-10s %-4s %s", e.Resolution(), e.ID, stars, e.Path)
+			if r.Note != "" {
+				line += "  # " + r.Note
+			}
+			fmt.Println(line)
+		}
+		return nil
+
+	case "rate":
+		if len(args) < 3 {
+			return errors.New(i18n.T("library rate needs a wallpaper and a rating from 0 to 5"))
+		}
+		stars, err := strconv.Atoi(args[2])
+		if err != nil || stars < 0 || stars > store.MaxStars {
+			return fmt.Errorf(i18n.T("invalid rating %q: use 0 to %d"), args[2], store.MaxStars)
+		}
+		key, err := ratingTarget(args[1], lib, downloadDir)
+		if err != nil {
+			return err
+		}
+		if err := ratings.Rate(key, stars); err != nil {
+			return err
+		}
+		if len(args) > 3 {
+			if err := ratings.SetNote(key, strings.Join(args[3:], " ")); err != nil {
+				return err
+			}
+		}
+		if verbose {
+			fmt.Printf(i18n.T("Rated %s %d/%d")+"\n", key, stars, store.MaxStars)
+		}
+		return nil
+	}
+	return fmt.Errorf(i18n.T("unknown library subcommand %q"), args[0])
+}
+
+// ratingTarget resolves the wallpaper `library rate` names to its rating
+// key: a file, or a Wallhaven ID or URL found in the library or among the
+// downloads.
+func ratingTarget(arg string, lib *store.Library, downloadDir string) (string, error) {
+	if fi, err := os.Stat(arg); err == nil && !fi.IsDir() {
+		return wallpaper.Key(arg), nil
+	}
+	id, err := parseWallpaperID(arg)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range lib.Entries() {
+		if e.ID == id {
+			return wallpaper.Key(e.Path), nil
+		}
+	}
+	if entries, err := os.ReadDir(downloadDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && strings.Contains(e.Name(), id) {
+				return e.Name(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf(i18n.T("%s is not in the library or download folder"), id)
+}
+
+// lookupLibrary confirms the Wallhaven IDs parsed from file names, storing
+// each wallpaper's page URL.
+func lookupLibrary(lib *store.Library, client *api.Client, verbose bool) error {
+	urls := map[string]string{}
+	for _, e := range lib.Entries() {
+		if e.ID == "" || e.URL != "" {
+			continue
+		}
+		wp, err := client.GetWallpaper(e.ID)
+		if err != nil {
+			if verbose {
+				fmt.Printf("  %s: %v\n", e.Path, err)
+			}
+			continue
+		}
+		urls[e.Path] = wp.URL
+	}
+	fmt.Printf(i18n.T("Confirmed %d Wallhaven IDs")+"\n", len(urls))
+	return lib.Confirm(urls)
+}
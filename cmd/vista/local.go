@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runLocal handles `vista local`: the grid over the download directory and
+// imported library folders, with no API client, so it works offline.
+// Wallpapers can be set again or deleted with d.
+func runLocal(dir, thumbDir string, lib *store.Library, r renderer.ImageRenderer, o ui.Options, verbose bool) error {
+	wallpapers, err := localWallpapers(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	wallpapers = append(wallpapers, libraryWallpapers(lib, dir)...)
+	if len(wallpapers) == 0 {
+		if verbose {
+			fmt.Println(i18n.T("No downloaded wallpapers found."))
+		}
+		return nil
+	}
+	if verbose {
+		fmt.Printf(i18n.T("Found %d downloaded wallpapers. Loading...")+"\n", len(wallpapers))
+	}
+	localThumbnails(wallpapers, thumbDir)
+
+	o.Detailer = nil
+	o.Search = nil
+	grid := ui.NewGrid(wallpapers, r, nil, 1, o)
+	defer grid.Cleanup()
+	_, err = grid.Run()
+	return err
+}
+
+// localThumbnails points each local wallpaper's thumbnail at a small cached
+// copy, so the grid doesn't render full-size images, and fills in its
+// resolution. A wallpaper whose thumbnail can't be made keeps the original.
+func localThumbnails(wallpapers []api.Wallpaper, thumbDir string) {
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i := range wallpapers {
+		wp := &wallpapers[i]
+		if !wp.Local() {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if wp.Resolution == "" {
+				wp.Resolution, _ = wallpaper.Resolution(wp.Path)
+			}
+			if thumb, err := wallpaper.Thumbnail(wp.Path, thumbDir); err == nil {
+				wp.Thumbs = api.Thumbs{Small: thumb, Large: wp.Path, Original: wp.Path}
+			}
+		}()
+	}
+	wg.Wait()
+}
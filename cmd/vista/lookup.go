@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+// lookupMaxDistance is the largest DHash distance still treated as the same
+// image; resized and recompressed copies land well under it.
+const lookupMaxDistance = 10
+
+// wallhavenFileID matches Wallhaven's download names (wallhaven-8xkxjo.jpg)
+// and bare IDs used as file names (8xkxjo.png).
+var wallhavenFileID = regexp.MustCompile(`^(?:wallhaven-)?([0-9a-z]{6})\.[a-z]+$`)
+
+// runLookup handles `vista lookup <file>`: it finds the Wallhaven page a
+// local image came from and prints its metadata. The ID is taken from the
+// file name when it has one; otherwise results of the same resolution and
+// dominant colour are compared against the file by perceptual hash.
+func runLookup(args []string, client *api.Client, verbose bool) error {
+	if len(args) != 1 {
+		return errors.New(i18n.T("lookup needs exactly one file"))
+	}
+	path := args[0]
+	if err := wallpaper.Validate(path); err != nil {
+		return err
+	}
+
+	if m := wallhavenFileID.FindStringSubmatch(strings.ToLower(filepath.Base(path))); m != nil {
+		if d, err := client.GetWallpaper(m[1]); err == nil {
+			printLookup(d, "")
+			return nil
+		} else if verbose {
+			fmt.Printf(i18n.T("File name ID %s not found, comparing images instead")+"\n", m[1])
+		}
+	}
+
+	id, dist, err := similarSearch(path, client, verbose)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return errors.New(i18n.T("no matching wallpaper found on Wallhaven"))
+	}
+	d, err := client.GetWallpaper(id)
+	if err != nil {
+		return err
+	}
+	printLookup(d, fmt.Sprintf(i18n.T("image match, %d/64 bits differ"), dist))
+	return nil
+}
+
+// similarSearch searches Wallhaven for wallpapers with the file's exact
+// resolution and dominant colour and returns the one whose thumbnail hash is
+// closest, if it is close enough.
+func similarSearch(path string, client *api.Client, verbose bool) (string, int, error) {
+	res, err := wallpaper.Resolution(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	img, err := wallpaper.DecodeReduced(path, 512)
+	if err != nil {
+		return "", 0, err
+	}
+	opts := api.SearchOptions{
+		Sorting:     "relevance",
+		Colors:      api.DominantColor(img),
+		Resolutions: res,
+	}
+	if verbose {
+		fmt.Printf(i18n.T("Searching %s wallpapers with colour #%s...")+"\n", opts.Resolutions, opts.Colors)
+	}
+	want, err := wallpaper.DHash(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	tmp, err := os.MkdirTemp("", "vista-lookup-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.RemoveAll(tmp)
+
+	bestID, bestDist := "", lookupMaxDistance+1
+	for page := 1; page <= 3; page++ {
+		results, meta, err := client.SearchPage(opts, page)
+		if err != nil {
+			return "", 0, err
+		}
+		urls := make([]string, len(results))
+		for i, wp := range results {
+			urls[i] = wp.Thumbs.Small
+		}
+		for i, thumb := range wallpaper.DownloadAll(urls, tmp, 0) {
+			if thumb == "" {
+				continue
+			}
+			h, err := wallpaper.DHash(thumb)
+			if err != nil {
+				continue
+			}
+			if d := wallpaper.HashDistance(want, h); d < bestDist {
+				bestID, bestDist = results[i].ID, d
+			}
+		}
+		if bestDist <= 2 || page >= meta.LastPage {
+			break
+		}
+	}
+	if bestDist > lookupMaxDistance {
+		return "", 0, nil
+	}
+	return bestID, bestDist, nil
+}
+
+func printLookup(d api.Details, how string) {
+	fmt.Println(d.URL)
+	if how != "" {
+		fmt.Printf("  (%s)\n", how)
+	}
+	fmt.Printf("  %-11s %s\n", i18n.T("Resolution"), d.Resolution)
+	fmt.Printf("  %-11s %s\n", i18n.T("Uploader"), d.Uploader)
+	if d.Source != "" {
+		fmt.Printf("  %-11s %s\n", i18n.T("Source"), d.Source)
+	}
+	if len(d.Tags) > 0 {
+		names := make([]string, len(d.Tags))
+		for i, t := range d.Tags {
+			names[i] = t.Name
+		}
+		fmt.Printf("  %-11s %s\n", i18n.T("Tags"), strings.Join(names, ", "))
+	}
+}
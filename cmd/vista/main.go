@@ -1,77 +1,107 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/davenicholson-xyz/vista/internal/api"
 	"github.com/davenicholson-xyz/vista/internal/config"
-	"github.com/davenicholson-xyz/vista/internal/renderer"
-	"github.com/davenicholson-xyz/vista/internal/ui"
+	"github.com/davenicholson-xyz/vista/internal/fixture"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
 )
 
-const usage = `Usage: vista [flags] <command> [query]
-
-Commands:
-  search,  s  <query>   search by keyword
-  top,     t  [query]   top-rated wallpapers
-  hot,     h  [query]   trending wallpapers
-  new,     n  [query]   newest wallpapers
-  random,  r  [query]   random wallpapers
-  history, hi           browse previously downloaded wallpapers
-
-Flags:
-  --apikey          Wallhaven API key
-  --purity          comma-separated: sfw,sketchy,nsfw
-  --categories      comma-separated: general,anime,people
-  --min-resolution  minimum resolution e.g. 1920x1080
-  --ratios          comma-separated aspect ratios e.g. 16x9,16x10
-  --download-dir    directory to save wallpapers
-  --script          script to run after setting wallpaper
-  --verbose, -v     print progress messages
-
-Flags override values from ~/.config/vista/config.yaml.
-`
-
 func main() {
 	apikeyFlag      := flag.String("apikey", "", "Wallhaven API key")
 	purityFlag      := flag.String("purity", "", "comma-separated: sfw,sketchy,nsfw")
 	categoriesFlag  := flag.String("categories", "", "comma-separated: general,anime,people")
 	minResFlag      := flag.String("min-resolution", "", "minimum resolution e.g. 1920x1080")
-	ratiosFlag      := flag.String("ratios", "", "comma-separated aspect ratios e.g. 16x9,16x10")
+	resolutionsFlag := flag.String("resolutions", "", "comma-separated exact resolutions e.g. 2560x1440,3840x2160")
+	ratiosFlag      := flag.String("ratios", "", "comma-separated aspect ratios e.g. 16x9,16x10, or landscape/portrait")
+	colorsFlag      := flag.String("colors", "", "dominant colour from Wallhaven's palette, e.g. 0066cc")
+	topRangeFlag    := flag.String("top-range", "", "toplist window: 1d, 3d, 1w, 1M, 3M, 6M or 1y")
 	downloadDirFlag := flag.String("download-dir", "", "directory to save wallpapers")
 	scriptFlag      := flag.String("script", "", "script to run after setting wallpaper")
+	confirmQuitFlag := flag.Bool("confirm-quit", false, "ask before quitting while downloads are in flight")
+	idleTimeoutFlag := flag.Int("idle-timeout", -1, "exit after N minutes without input (0 disables)")
+	stayFlag        := flag.Bool("stay", false, "Enter sets the wallpaper without leaving the grid")
+	slideshowFlag   := flag.Int("slideshow", 0, "seconds per wallpaper in the 'p' slideshow preview")
+	thumbSizeFlag   := flag.String("thumb-size", "", "grid thumbnail source: small, large or original")
+	transitionFlag  := flag.String("transition", "", "swww transition type, e.g. fade, wipe, grow")
+	transDurFlag    := flag.Float64("transition-duration", 0, "transition length in seconds")
+	transPosFlag    := flag.String("transition-pos", "", "transition origin, e.g. center or 0.8,0.9")
+	rendererFlag    := flag.String("renderer", "", "image renderer: auto, chafa, kitty, sixel, iterm, ueberzug")
+	asciiFlag       := flag.Bool("ascii", false, "plain ASCII UI")
+	browseFlag      := flag.Bool("browse-only", false, "browse without setting or deleting wallpapers or running scripts")
+	noExecFlag      := flag.Bool("no-exec", false, "never run external programs such as chafa, scripts, swww or a browser")
+	applyFlag       := flag.String("apply", "", "set the first result, or one picked by a strategy, and exit without the grid")
+	eventsFlag      := flag.String("events", "", "emit machine-readable events on stdout: json")
+	jsonFlag        := flag.Bool("json", false, "print the first page of results and its meta as JSON instead of opening the grid")
+	logFlag         := flag.String("log", "", "append diagnostics to this file")
+	recordFlag      := flag.String("record", "", "record the grid session to this asciinema cast file")
+	sourceFlag      := flag.String("source", "", "serve search results from fixture:<dir> instead of Wallhaven")
 	verboseFlag     := flag.Bool("verbose", false, "print progress messages")
 	flag.BoolVar(verboseFlag, "v", false, "print progress messages")
 
-	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage()) }
 	flag.Parse()
 
 	args := flag.Args()
 	if len(args) < 1 {
-		fmt.Fprint(os.Stderr, usage)
+		fmt.Fprint(os.Stderr, usage())
 		os.Exit(1)
 	}
 
 	cmd  := args[0]
 	rest := args[1:]
 
-	verbose := *verboseFlag
+	ev, err := newEvents(*eventsFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	if *browseFlag && (*applyFlag != "" || setsWallpaper(cmd)) {
+		what := "vista " + cmd
+		if *applyFlag != "" {
+			what = "--apply"
+		}
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", fmt.Sprintf(i18n.T("%s sets wallpapers, which --browse-only disables"), what))
+		os.Exit(1)
+	}
+
+	// Events own stdout, so human-readable progress is dropped with them.
+	verbose := *verboseFlag && ev == nil
 
 	cfg, err := config.Load()
 	if err != nil && verbose {
-		fmt.Fprintf(os.Stderr, "Warning: could not load config: %v\n", err)
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: could not load config: %v")+"\n", err)
 	}
 
 	// Flags override config file values when explicitly provided.
 	if *apikeyFlag != "" {
 		cfg.APIKey = *apikeyFlag
 	}
+	if cfg.AccountDefaults && cfg.APIKey != "" && *sourceFlag == "" && needsAccount(cmd) {
+		applyAccountDefaults(cfg, verbose)
+	}
 	if *purityFlag != "" {
+		if cfg.PurityLock && *purityFlag != "sfw" {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", i18n.T("purity is locked to sfw"))
+			os.Exit(1)
+		}
 		cfg.Purity = strings.Split(*purityFlag, ",")
 	}
 	if *categoriesFlag != "" {
@@ -80,46 +110,506 @@ func main() {
 	if *minResFlag != "" {
 		cfg.MinResolution = *minResFlag
 	}
+	if *resolutionsFlag != "" {
+		cfg.Resolutions = strings.Split(*resolutionsFlag, ",")
+	}
 	if *ratiosFlag != "" {
 		cfg.Ratios = strings.Split(*ratiosFlag, ",")
 	}
+	if *colorsFlag != "" {
+		cfg.Colors = *colorsFlag
+	}
+	if *topRangeFlag != "" {
+		cfg.TopRange = *topRangeFlag
+	}
+	if err := api.ValidateTopRange(cfg.TopRange); err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	resolutions, err := cfg.ResolutionsParam()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	ratios, err := cfg.RatiosParam()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	if cfg.Colors != "" {
+		c, err := api.NormalizeColor(cfg.Colors)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		cfg.Colors = c
+	}
 	if *downloadDirFlag != "" {
 		cfg.DownloadDir = *downloadDirFlag
 	}
 	if *scriptFlag != "" {
 		cfg.Script = *scriptFlag
 	}
+	if *confirmQuitFlag {
+		cfg.ConfirmQuit = true
+	}
+	if *idleTimeoutFlag >= 0 {
+		cfg.IdleTimeout = *idleTimeoutFlag
+	}
+	if *stayFlag {
+		cfg.StayOpen = true
+	}
+	if *slideshowFlag > 0 {
+		cfg.SlideInterval = *slideshowFlag
+	}
+	if *thumbSizeFlag != "" {
+		cfg.ThumbSize = *thumbSizeFlag
+	}
+	if *transitionFlag != "" {
+		cfg.Transition = *transitionFlag
+	}
+	if *transDurFlag > 0 {
+		cfg.TransDuration = *transDurFlag
+	}
+	if *transPosFlag != "" {
+		cfg.TransPos = *transPosFlag
+	}
+	if *logFlag != "" {
+		cfg.LogFile = *logFlag
+	}
+	if *rendererFlag != "" {
+		cfg.Renderer = *rendererFlag
+	}
+	if *asciiFlag {
+		cfg.ASCII = true
+	}
+	if cfg.BorderStyle != "" && !slices.Contains(ui.BorderStyles(), cfg.BorderStyle) {
+		fmt.Fprintf(os.Stderr, i18n.T("Invalid border style %q: use %s")+"\n", cfg.BorderStyle, strings.Join(ui.BorderStyles(), ", "))
+		os.Exit(1)
+	}
+	if cfg.Selection != "" && !slices.Contains(ui.SelectionStyles(), cfg.Selection) {
+		fmt.Fprintf(os.Stderr, i18n.T("Invalid selection style %q: use %s")+"\n", cfg.Selection, strings.Join(ui.SelectionStyles(), ", "))
+		os.Exit(1)
+	}
+	if cfg.Fallback != "" && !slices.Contains(fallbackModes, cfg.Fallback) {
+		fmt.Fprintf(os.Stderr, i18n.T("Invalid fallback %q: use %s")+"\n", cfg.Fallback, strings.Join(fallbackModes, ", "))
+		os.Exit(1)
+	}
+	switch cfg.ThumbSize {
+	case "", "small", "large", "original":
+	default:
+		fmt.Fprintf(os.Stderr, i18n.T("Invalid thumb size %q: use small, large or original")+"\n", cfg.ThumbSize)
+		os.Exit(1)
+	}
 
-	var r renderer.ImageRenderer
-	if renderer.IsChafaAvailable() {
-		r = &renderer.ChafaRenderer{}
-	} else {
-		if verbose {
-			fmt.Fprintln(os.Stderr, "Warning: chafa not found, falling back to placeholder renderer")
+	cellAspect, err := cfg.CellAspectRatio()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	maxCache, err := cfg.MaxCacheBytes()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+
+	wallpaper.SetConnsPerHost(cfg.ConnsPerHost)
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = userAgent()
+	}
+	wallpaper.SetUserAgent(cfg.UserAgent)
+	wallpaper.SetRetries(cfg.Retries)
+	wallpaper.SetNoExec(*noExecFlag)
+	if *noExecFlag && cfg.Script != "" && verbose {
+		fmt.Fprintf(os.Stderr, i18n.T("Warning: --no-exec: not running script %q")+"\n", cfg.Script)
+	}
+
+	var logger *log.Logger
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(config.ExpandPath(cfg.LogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error opening log file: %v")+"\n", err)
+			os.Exit(1)
 		}
-		r = &renderer.FallbackRenderer{}
+		defer f.Close()
+		logger = log.New(f, "vista: ", log.LstdFlags)
+	}
+
+	gridOpts := ui.Options{
+		DownloadDir:       cfg.ResolvedDownloadDir(),
+		Script:            cfg.ResolvedScript(),
+		ScriptShell:       cfg.ScriptShell,
+		ScriptTimeout:     time.Duration(cfg.ScriptTimeout) * time.Second,
+		Transition:        wallpaper.Transition{Type: cfg.Transition, Duration: cfg.TransDuration, Pos: cfg.TransPos},
+		Overlays:          overlays(cfg.Overlays),
+		OverlayDir:        filepath.Join(config.CacheDir(), "overlay"),
+		CurrentFile:       filepath.Join(config.CacheDir(), "current"),
+		HistoryFile:       filepath.Join(config.CacheDir(), "history"),
+		RepeatWindow:      cfg.RepeatWindow,
+		MinRating:         cfg.MinRating,
+		Verbose:           verbose,
+		ConfirmQuit:       cfg.ConfirmQuit,
+		IdleTimeout:       time.Duration(cfg.IdleTimeout) * time.Minute,
+		StayOpen:          cfg.StayOpen,
+		TryDuration:       time.Duration(cfg.TrySeconds) * time.Second,
+		SlideshowInterval: time.Duration(cfg.SlideInterval) * time.Second,
+		ThumbSize:         cfg.ThumbSize,
+		Logger:            logger,
+		EnlargeSelected:   cfg.EnlargeSel,
+		Gap:               cfg.Gap,
+		CellAspect:        cellAspect,
+		BorderStyle:       cfg.BorderStyle,
+		Selection:         cfg.Selection,
+		BorderUnselected:  cfg.BorderAll,
+		ASCII:             cfg.ASCII,
+		DownloadWorkers:   cfg.Workers,
+		PrefetchPages:     cfg.Prefetch,
+		MaxPrefetchPages:  cfg.MaxPrefetch,
+		BrowseOnly:        *browseFlag,
+		NoExec:            *noExecFlag,
+	}
+
+	favorites, err := store.OpenFavorites(filepath.Join(config.DataDir(), "favorites.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
 	}
+	gridOpts.Favorites = favorites
 
-	// history is handled locally — no API call needed.
+	playlists, err := store.OpenPlaylists(filepath.Join(config.DataDir(), "playlists.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	gridOpts.Playlists = playlists
+
+	ratings, err := store.OpenRatings(filepath.Join(config.DataDir(), "ratings.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+	gridOpts.Ratings = ratings
+
+	library, err := store.OpenLibrary(filepath.Join(config.DataDir(), "library.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+
+	if maxCache > 0 {
+		wallpaper.SetCacheLimit(cfg.ResolvedDownloadDir(), maxCache, gridOpts.HistoryFile, func(name string) bool {
+			if cur := wallpaper.Current(gridOpts.CurrentFile); cur != "" && wallpaper.Key(cur) == name {
+				return true
+			}
+			return slices.ContainsFunc(favorites.List(), func(wp api.Wallpaper) bool {
+				return wallpaper.SavedKey(wp.Path, wp.Source, wp.ID) == name
+			})
+		})
+	}
+
+	fb := newFallback(cfg.Fallback, cfg.ResolvedDownloadDir(), library, ev)
+
+	r, err := pickRenderer(cfg.Renderer, cfg.ASCII, *noExecFlag, verbose)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
+	}
+
+	if cmd == "set" {
+		if err := runSet(rest, gridOpts); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "undo" {
+		if err := runUndo(gridOpts); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "replay" {
+		if err := runReplay(rest); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *recordFlag != "" {
+		f, err := os.Create(*recordFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		gridOpts.Record = f
+	}
+
+	if cmd == "ctl" {
+		if err := runCtl(rest); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "local" || cmd == "lo" {
+		if err := runLocal(cfg.ResolvedDownloadDir(), filepath.Join(config.CacheDir(), "thumbs"), library, r, gridOpts, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "review" {
+		if err := runReview(rest, cfg.ResolvedDownloadDir(), filepath.Join(config.CacheDir(), "thumbs"), r, gridOpts, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	client := &api.Client{
+		APIKey:        cfg.APIKey,
+		Username:      cfg.Username,
+		Purity:        cfg.PurityParam(),
+		Categories:    cfg.CategoriesParam(),
+		MinResolution: cfg.MinResolution,
+		Ratios:        ratios,
+		Colors:        cfg.Colors,
+		Resolutions:   resolutions,
+		TopRange:      cfg.TopRange,
+		UserAgent:     cfg.UserAgent,
+		Retries:       cfg.Retries,
+	}
+	gridOpts.Detailer = client
+	gridOpts.Search = func(q ui.Query) provider.Provider {
+		c := *client
+		if q.Purity != "" {
+			c.Purity = q.Purity
+		}
+		if q.Categories != "" {
+			c.Categories = q.Categories
+		}
+		return &api.Search{Client: &c, Opts: api.SearchOptions{Query: q.Text, Sorting: q.Sorting}}
+	}
+	gridOpts.PurityLocked = cfg.PurityLock
+
+	// A fixture stands in for Wallhaven in the grid, --apply and --json,
+	// whatever the query, so bugs reproduce without the network.
+	var fixtureSrc *fixture.Source
+	if *sourceFlag != "" {
+		dir, ok := strings.CutPrefix(*sourceFlag, "fixture:")
+		if !ok {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", fmt.Sprintf(i18n.T("unknown --source %q: use fixture:<dir>"), *sourceFlag))
+			os.Exit(1)
+		}
+		if fixtureSrc, err = fixture.Open(dir); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		gridOpts.Detailer = fixtureSrc
+		gridOpts.Search = func(ui.Query) provider.Provider { return fixtureSrc }
+	}
+
+	// history is read from the local history file — no API call needed,
+	// but the client backs 'i' and tag searches from the grid.
 	if cmd == "history" || cmd == "hi" {
-		wallpapers, err := localWallpapers(cfg.ResolvedDownloadDir())
+		if len(rest) > 0 && rest[0] == "list" {
+			if err := runHistoryList(rest[1:], gridOpts.HistoryFile); err != nil {
+				fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		wallpapers, err := historyWallpapers(gridOpts.HistoryFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+			fmt.Fprintf(os.Stderr, i18n.T("Error reading history: %v")+"\n", err)
 			os.Exit(1)
 		}
+		if *applyFlag != "" {
+			headlessApply(wallpapers, *applyFlag, gridOpts, ev, verbose)
+			return
+		}
 		if len(wallpapers) == 0 {
 			if verbose {
-				fmt.Println("No downloaded wallpapers found.")
+				fmt.Println(i18n.T("No wallpaper has been set yet."))
 			}
 			os.Exit(0)
 		}
 		if verbose {
-			fmt.Printf("Found %d downloaded wallpapers. Loading...\n", len(wallpapers))
+			fmt.Printf(i18n.T("Found %d wallpapers in your history. Loading...")+"\n", len(wallpapers))
+		}
+		localThumbnails(wallpapers, filepath.Join(config.CacheDir(), "thumbs"))
+		grid := ui.NewGrid(wallpapers, r, nil, 1, gridOpts)
+		defer grid.Cleanup()
+		if _, err := grid.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "favs" || cmd == "fa" {
+		wallpapers := favorites.List()
+		if len(wallpapers) == 0 {
+			fmt.Println(i18n.T("No favorites yet - press f in the grid to add one."))
+			os.Exit(0)
 		}
-		grid := ui.NewGrid(wallpapers, r, cfg.ResolvedDownloadDir(), cfg.Script, nil, api.SearchOptions{}, 1, verbose)
+		grid := ui.NewGrid(wallpapers, r, nil, 1, gridOpts)
 		defer grid.Cleanup()
 		if _, err := grid.Run(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+
+	if cmd == "collections" {
+		collections, err := client.Collections()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		for _, c := range collections {
+			fmt.Printf(i18n.T("%-8d %-30s %d wallpapers")+"\n", c.ID, c.Label, c.Count)
+		}
+		return
+	}
+
+	if cmd == "lookup" {
+		if err := runLookup(rest, client, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "credits" {
+		if err := runCredits(gridOpts.CurrentFile, client); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "reddit" {
+		if err := runReddit(rest, cfg, r, gridOpts, *applyFlag, ev, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "apod" {
+		if err := runAPOD(rest, cfg, r, gridOpts, *applyFlag, ev, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "id" {
+		wp, err := lookupID(rest, client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		if *applyFlag != "" {
+			headlessApply([]api.Wallpaper{wp}, *applyFlag, gridOpts, ev, verbose)
+			return
+		}
+		gridOpts.OpenDetail = true
+		grid := ui.NewGrid([]api.Wallpaper{wp}, r, nil, 1, gridOpts)
+		defer grid.Cleanup()
+		if _, err := grid.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "library" {
+		if err := runLibrary(rest, library, ratings, client, cfg.ResolvedDownloadDir(), verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "play" {
+		if err := runPlay(rest, cfg, playlists, client, gridOpts, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "export-list" {
+		if err := runExportList(rest, client, gridOpts, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "push" || cmd == "pull" {
+		if err := runSync(cmd, rest, cfg, store.Stores{Library: library, Ratings: ratings, Favorites: favorites}, gridOpts, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "sync-serve" {
+		if err := runSyncServe(rest, cfg, store.Stores{Library: library, Ratings: ratings, Favorites: favorites}); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "export-html" {
+		if err := runExportHTML(rest, client, filepath.Join(config.CacheDir(), "thumbs"), gridOpts, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "board" {
+		if err := runBoard(rest, client, r, filepath.Join(config.CacheDir(), "board"), logger); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "daemon" {
+		if err := runDaemon(rest, client, gridOpts, cfg.Strategy, fb, ev, verbose); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "daily" {
+		if err := runDaily(rest, client, gridOpts, ev, verbose); err != nil {
+			ev.error(err)
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "settings" {
+		if err := runSettings(client, cfg.PurityLock); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
 			os.Exit(1)
 		}
 		return
@@ -131,69 +621,248 @@ func main() {
 	switch cmd {
 	case "search", "s":
 		if len(rest) == 0 {
-			fmt.Fprint(os.Stderr, usage)
+			fmt.Fprint(os.Stderr, usage())
 			os.Exit(1)
 		}
 		opts  = api.SearchOptions{Query: strings.Join(rest, " "), Sorting: "random"}
-		label = fmt.Sprintf("Searching for %q", opts.Query)
+		label = fmt.Sprintf(i18n.T("Searching for %q"), opts.Query)
 	case "top", "t":
 		opts  = api.SearchOptions{Query: strings.Join(rest, " "), Sorting: "toplist"}
-		label = "Fetching top wallpapers"
+		label = i18n.T("Fetching top wallpapers")
 	case "hot", "h":
 		opts  = api.SearchOptions{Query: strings.Join(rest, " "), Sorting: "hot"}
-		label = "Fetching hot wallpapers"
+		label = i18n.T("Fetching hot wallpapers")
 	case "new", "n":
 		opts  = api.SearchOptions{Query: strings.Join(rest, " "), Sorting: "date_added"}
-		label = "Fetching new wallpapers"
+		label = i18n.T("Fetching new wallpapers")
 	case "random", "r":
 		opts  = api.SearchOptions{Query: strings.Join(rest, " "), Sorting: "random"}
-		label = "Fetching random wallpapers"
+		label = i18n.T("Fetching random wallpapers")
+	case "user":
+		name := ""
+		if len(rest) > 0 {
+			name = strings.TrimPrefix(rest[0], "@")
+		}
+		if name == "" || len(rest) > 1 {
+			fmt.Fprint(os.Stderr, usage())
+			os.Exit(1)
+		}
+		opts  = api.SearchOptions{Query: "@" + name, Sorting: "date_added"}
+		label = fmt.Sprintf(i18n.T("Fetching uploads by %s"), name)
+	case "discover":
+		var err error
+		if opts, label, err = discoverSearch(rest, client); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		if label == "" {
+			return // nothing picked
+		}
+	case "tag", "tg":
+		q, err := api.TagQuery(rest)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		opts  = api.SearchOptions{Query: q, Sorting: "date_added"}
+		label = fmt.Sprintf(i18n.T("Fetching wallpapers tagged %s"), q)
+		if id, ok := strings.CutPrefix(q, "id:"); ok {
+			n, _ := strconv.Atoi(id)
+			tag, err := client.Tag(n)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+				os.Exit(1)
+			}
+			label = fmt.Sprintf(i18n.T("Fetching wallpapers tagged %s"), tag.Name)
+		}
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %q\n\n%s", cmd, usage)
+		fmt.Fprintf(os.Stderr, i18n.T("Unknown command: %q")+"\n\n%s", cmd, usage())
 		os.Exit(1)
 	}
 
-	client := &api.Client{
-		APIKey:        cfg.APIKey,
-		Username:      cfg.Username,
-		Purity:        cfg.PurityParam(),
-		Categories:    cfg.CategoriesParam(),
-		MinResolution: cfg.MinResolution,
-		Ratios:        cfg.RatiosParam(),
+	if err := api.ValidateQuery(opts.Query); err != nil {
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+		os.Exit(1)
 	}
 
 	if verbose {
 		fmt.Printf("%s...\n", label)
 	}
-	wallpapers, meta, err := client.SearchPage(opts, 1)
+	if *applyFlag != "" {
+		ev.emit(event{Event: "search_started", Query: opts.Query, Sorting: opts.Sorting})
+	}
+
+	gridOpts.Query = &ui.Query{Text: opts.Query, Sorting: opts.Sorting, Purity: client.Purity, Categories: client.Categories}
+	// Random results differ every time, so only other sortings are worth
+	// showing from the last launch while the search runs.
+	if *applyFlag == "" && !*jsonFlag && opts.Sorting != "random" && fixtureSrc == nil {
+		gridOpts.FirstScreen = filepath.Join(config.CacheDir(), "first-screen")
+		gridOpts.FirstScreenKey = searchKey(client, opts)
+		if fs, ok := ui.LoadFirstScreen(gridOpts.FirstScreen, gridOpts.FirstScreenKey); ok {
+			gridOpts.Meta = fs.Meta
+			grid := ui.NewGrid(fs.Wallpapers, r, &api.Search{Client: client, Opts: opts}, fs.Meta.LastPage, gridOpts)
+			defer grid.Cleanup()
+			grid.Refresh(&api.Search{Client: client, Opts: opts})
+			if _, err := grid.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	var src provider.Provider = &api.Search{Client: client, Opts: opts}
+	if fixtureSrc != nil {
+		src = fixtureSrc
+	}
+	wallpapers, meta, err := src.Page(1)
+	if err != nil && *applyFlag != "" {
+		wallpapers, err = fb.results(err)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		ev.error(err)
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
 		os.Exit(1)
 	}
 
+	if *jsonFlag {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Meta api.Meta        `json:"meta"`
+			Data []api.Wallpaper `json:"data"`
+		}{meta, wallpapers}); err != nil {
+			fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *applyFlag != "" {
+		headlessApply(wallpapers, *applyFlag, gridOpts, ev, verbose)
+		return
+	}
+
 	if len(wallpapers) == 0 {
 		if verbose {
-			fmt.Println("No results found.")
+			fmt.Println(i18n.T("No results found."))
 		}
 		os.Exit(0)
 	}
 
 	if verbose {
-		fmt.Printf("Found %d wallpapers across %d pages. Loading...\n", meta.Total, meta.LastPage)
+		fmt.Printf(i18n.T("Found %d wallpapers across %d pages. Loading...")+"\n", meta.Total, meta.LastPage)
 	}
 
-	grid := ui.NewGrid(wallpapers, r, cfg.ResolvedDownloadDir(), cfg.Script, client, opts, meta.LastPage, verbose)
+	gridOpts.Meta = meta
+	grid := ui.NewGrid(wallpapers, r, src, meta.LastPage, gridOpts)
 	defer grid.Cleanup()
 
 	_, err = grid.Run()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, i18n.T("Error: %v")+"\n", err)
 		os.Exit(1)
 	}
 }
 
-var imageExts = map[string]bool{
-	".jpg": true, ".jpeg": true, ".png": true, ".webp": true,
+// searchKey identifies a search for caches such as the saved first screen:
+// its options and the client's default filters.
+func searchKey(client *api.Client, opts api.SearchOptions) string {
+	c := *client
+	c.APIKey = "" // the cache file is not the place for it
+	b, _ := json.Marshal(struct {
+		Client api.Client
+		Opts   api.SearchOptions
+	}{c, opts})
+	return string(b)
+}
+
+// overlays converts configured overlays, expanding their paths.
+func overlays(list []config.Overlay) []wallpaper.Overlay {
+	var out []wallpaper.Overlay
+	for _, o := range list {
+		out = append(out, wallpaper.Overlay{
+			Kind:     o.Type,
+			Path:     config.ExpandPath(o.Path),
+			Position: o.Position,
+			Opacity:  o.Opacity,
+			Scale:    o.Scale,
+			Color:    o.Color,
+			Margin:   o.Margin,
+		})
+	}
+	return out
+}
+
+// pickRenderer returns the renderer named in config. "auto" (or "") prefers
+// chafa, then a native protocol renderer the terminal supports, then
+// ueberzugpp, then the placeholder renderer. With noExec, chafa and
+// ueberzugpp are never used, even when named.
+func pickRenderer(name string, ascii, noExec, verbose bool) (renderer.ImageRenderer, error) {
+	if noExec && (name == "chafa" || name == "ueberzug") {
+		if verbose {
+			fmt.Fprintf(os.Stderr, i18n.T("Warning: --no-exec: not running %s, using a built-in renderer")+"\n", name)
+		}
+		name = "auto"
+	}
+	switch name {
+	case "chafa":
+		return &renderer.ChafaRenderer{CacheDir: config.CacheDir(), ASCII: ascii}, nil
+	case "ueberzug":
+		return &renderer.UeberzugRenderer{}, nil
+	case "kitty":
+		return &renderer.KittyRenderer{}, nil
+	case "sixel":
+		return &renderer.SixelRenderer{}, nil
+	case "iterm":
+		return &renderer.ITermRenderer{}, nil
+	case "", "auto":
+	default:
+		return nil, fmt.Errorf("unknown renderer %q", name)
+	}
+
+	switch {
+	case !noExec && renderer.IsChafaAvailable():
+		return &renderer.ChafaRenderer{CacheDir: config.CacheDir(), ASCII: ascii}, nil
+	case renderer.DetectProtocol() == "kitty" && !ascii:
+		return &renderer.KittyRenderer{}, nil
+	case renderer.DetectProtocol() == "sixels" && !ascii:
+		return &renderer.SixelRenderer{}, nil
+	case renderer.DetectProtocol() == "iterm" && !ascii:
+		return &renderer.ITermRenderer{}, nil
+	case !noExec && renderer.IsUeberzugAvailable():
+		return &renderer.UeberzugRenderer{}, nil
+	}
+	if verbose && !noExec {
+		fmt.Fprintln(os.Stderr, i18n.T("Warning: chafa not found, falling back to placeholder renderer"))
+	}
+	return &renderer.FallbackRenderer{}, nil
+}
+
+// libraryWallpapers returns imported library images outside downloadDir,
+// which localWallpapers already covers.
+func libraryWallpapers(lib *store.Library, downloadDir string) []api.Wallpaper {
+	var wallpapers []api.Wallpaper
+	for _, e := range lib.Entries() {
+		if filepath.Dir(e.Path) == filepath.Clean(downloadDir) {
+			continue
+		}
+		if _, err := os.Stat(e.Path); err != nil {
+			continue
+		}
+		id := e.ID
+		if id == "" {
+			id = filepath.Base(e.Path)
+		}
+		wallpapers = append(wallpapers, api.Wallpaper{
+			ID:         id,
+			URL:        e.URL,
+			Path:       e.Path,
+			Resolution: e.Resolution(),
+			Thumbs:     api.Thumbs{Small: e.Path},
+		})
+	}
+	return wallpapers
 }
 
 // localWallpapers reads dir and returns Wallpaper entries for each image,
@@ -214,7 +883,7 @@ func localWallpapers(dir string) ([]api.Wallpaper, error) {
 		if e.IsDir() {
 			continue
 		}
-		if !imageExts[strings.ToLower(filepath.Ext(e.Name()))] {
+		if !wallpaper.IsImage(e.Name()) {
 			continue
 		}
 		info, err := e.Info()
@@ -242,3 +911,13 @@ func localWallpapers(dir string) ([]api.Wallpaper, error) {
 	}
 	return wallpapers, nil
 }
+
+// setsWallpaper reports whether cmd changes the desktop without the grid,
+// which --browse-only rules out.
+func setsWallpaper(cmd string) bool {
+	switch cmd {
+	case "set", "undo", "play", "daemon", "ctl", "daily":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// defaultPlayInterval is how long each item shows when neither the item
+// nor its playlist sets a duration.
+const defaultPlayInterval = 30 * time.Minute
+
+// playItem is a playlist entry resolved from config or the saved store.
+type playItem struct {
+	id, path string
+	duration time.Duration
+}
+
+// runPlay handles `vista play [--once] [name]`: it sets each wallpaper of
+// the playlist in turn, looping until interrupted. Without a name it lists
+// the available playlists.
+func runPlay(args []string, cfg *config.Config, saved *store.Playlists, client *api.Client, o ui.Options, verbose bool) error {
+	fs := flag.NewFlagSet("play", flag.ContinueOnError)
+	once := fs.Bool("once", false, "play the list once instead of looping")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		for name, p := range cfg.Playlists {
+			fmt.Printf("%-20s %d\n", name, len(p.Items))
+		}
+		for _, name := range saved.Names() {
+			if _, ok := cfg.Playlists[name]; !ok {
+				items, _ := saved.Get(name)
+				fmt.Printf("%-20s %d\n", name, len(items))
+			}
+		}
+		return nil
+	}
+
+	name := fs.Arg(0)
+	items, err := playlistItems(name, cfg, saved)
+	if err != nil {
+		return err
+	}
+
+	opts := setOptions(o)
+	for {
+		played := 0
+		for _, item := range items {
+			path, err := resolvePlayItem(item, client, o.DownloadDir)
+			if err == nil {
+				err = wallpaper.Set(path, wallpaper.Info{ID: item.id}, opts)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, i18n.T("Skipping %s: %v")+"\n", item.label(), err)
+				continue
+			}
+			played++
+			if verbose {
+				fmt.Printf(i18n.T("Playing %s for %s")+"\n", item.label(), item.duration)
+			}
+			time.Sleep(item.duration)
+		}
+		if played == 0 {
+			return fmt.Errorf("no wallpaper in playlist %q could be set", name)
+		}
+		if *once {
+			return nil
+		}
+	}
+}
+
+// playlistItems looks name up in config, then in the playlists saved from
+// the grid, and resolves each item's duration.
+func playlistItems(name string, cfg *config.Config, saved *store.Playlists) ([]playItem, error) {
+	var items []playItem
+	if p, ok := cfg.Playlists[name]; ok {
+		for _, it := range p.Items {
+			d, err := playDuration(it.Duration, p.Interval)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, playItem{id: it.ID, path: config.ExpandPath(it.Path), duration: d})
+		}
+	} else if list, ok := saved.Get(name); ok {
+		for _, it := range list {
+			d, err := playDuration(it.Duration, "")
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, playItem{id: it.ID, path: it.Path, duration: d})
+		}
+	} else {
+		return nil, fmt.Errorf("no playlist named %q", name)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("playlist %q is empty", name)
+	}
+	return items, nil
+}
+
+func playDuration(item, playlist string) (time.Duration, error) {
+	for _, s := range []string{item, playlist} {
+		if s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid duration %q: use e.g. 90s, 10m or 1h", s)
+		}
+		return d, nil
+	}
+	return defaultPlayInterval, nil
+}
+
+// resolvePlayItem returns a local file for item, downloading it first when
+// it's a URL or a bare Wallhaven ID.
+func resolvePlayItem(item playItem, client *api.Client, downloadDir string) (string, error) {
+	src := item.path
+	if src == "" {
+		if item.id == "" {
+			return "", errors.New("item has neither id nor path")
+		}
+		wp, err := client.GetWallpaper(item.id)
+		if err != nil {
+			return "", err
+		}
+		src = wp.Path
+	}
+	return wallpaper.Download(src, downloadDir)
+}
+
+func (it playItem) label() string {
+	if it.path != "" && !strings.HasPrefix(it.path, "http") {
+		return it.path
+	}
+	return it.id
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/reddit"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runReddit handles `vista reddit <subreddit> [--sort top] [--time week]`:
+// the direct image posts of a subreddit in the grid, or one set with
+// --apply. Posts below the configured min_resolution are skipped, as are
+// NSFW ones unless purity includes nsfw.
+func runReddit(args []string, cfg *config.Config, r renderer.ImageRenderer, o ui.Options, apply string, ev *events, verbose bool) error {
+	fs := flag.NewFlagSet("reddit", flag.ContinueOnError)
+	sort := fs.String("sort", "hot", "listing order: "+strings.Join(reddit.Sorts, ", "))
+	window := fs.String("time", "day", "window of --sort top: "+strings.Join(reddit.Times, ", "))
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	name = strings.TrimPrefix(strings.TrimPrefix(strings.Trim(name, "/"), "r/"), "/")
+	if name == "" {
+		return errors.New(i18n.T("reddit needs a subreddit, e.g. vista reddit wallpapers"))
+	}
+
+	src := &reddit.Source{
+		Subreddit: name,
+		Sort:      *sort,
+		Time:      *window,
+		NSFW:      slices.Contains(cfg.Purity, "nsfw"),
+		UserAgent: cfg.UserAgent,
+		Retries:   cfg.Retries,
+	}
+	if cfg.MinResolution != "" {
+		if _, err := fmt.Sscanf(cfg.MinResolution, "%dx%d", &src.MinWidth, &src.MinHeight); err != nil {
+			return fmt.Errorf(i18n.T("invalid min resolution %q: use WxH"), cfg.MinResolution)
+		}
+	}
+	if err := src.Validate(); err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Printf(i18n.T("Fetching image posts from r/%s")+"\n", name)
+	}
+	wallpapers, meta, err := src.Page(1)
+	if err != nil {
+		return err
+	}
+	if apply != "" {
+		headlessApply(wallpapers, apply, o, ev, verbose)
+		return nil
+	}
+	if len(wallpapers) == 0 {
+		if verbose {
+			fmt.Println(i18n.T("No results found."))
+		}
+		return nil
+	}
+
+	// Wallhaven's details and searches don't apply to Reddit posts.
+	o.Detailer = nil
+	o.Search = nil
+	o.Meta = meta
+	grid := ui.NewGrid(wallpapers, r, src, meta.LastPage, o)
+	defer grid.Cleanup()
+	_, err = grid.Run()
+	return err
+}
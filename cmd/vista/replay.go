@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runReplay handles `vista replay <file.cast>`: it plays a session saved
+// with --record back in the terminal, so a rendering bug can be seen
+// without asciinema installed.
+func runReplay(args []string) error {
+	if len(args) != 1 {
+		return errors.New(i18n.T("usage: vista replay <file.cast>"))
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	defer os.Stdout.WriteString("\033[0m\033[?25h\n") //nolint:errcheck
+	return ui.Replay(f, os.Stdout)
+}
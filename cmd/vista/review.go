@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runReview handles `vista review [--sort size|set]`: the grid over the
+// download directory as a cleanup pass, largest files first or, with
+// --sort set, those set longest ago (or never) first. Space keeps a
+// wallpaper and d deletes it; the space reclaimed is printed on exit.
+func runReview(args []string, dir, thumbDir string, r renderer.ImageRenderer, o ui.Options, verbose bool) error {
+	fs := flag.NewFlagSet("review", flag.ContinueOnError)
+	order := fs.String("sort", "size", "order to review in: size or set")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *order != "size" && *order != "set" {
+		return fmt.Errorf(i18n.T("unknown --sort %q: use size or set"), *order)
+	}
+
+	wallpapers, err := localWallpapers(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(wallpapers) == 0 {
+		if verbose {
+			fmt.Println(i18n.T("No downloaded wallpapers found."))
+		}
+		return nil
+	}
+	if *order == "size" {
+		sortBySize(wallpapers)
+	} else {
+		sortByLastSet(wallpapers, o.HistoryFile)
+	}
+	localThumbnails(wallpapers, thumbDir)
+
+	o.Detailer = nil
+	o.Search = nil
+	o.Review = true
+	grid := ui.NewGrid(wallpapers, r, nil, 1, o)
+	defer grid.Cleanup()
+	if _, err := grid.Run(); err != nil {
+		return err
+	}
+	s := grid.Review()
+	fmt.Printf(i18n.T("Kept %d, deleted %d, reclaimed %.1f MB")+"\n", s.Kept, s.Deleted, float64(s.Freed)/(1<<20))
+	return nil
+}
+
+// sortBySize orders wallpapers largest file first.
+func sortBySize(wallpapers []api.Wallpaper) {
+	size := make(map[string]int64, len(wallpapers))
+	for _, wp := range wallpapers {
+		if fi, err := os.Stat(wp.Path); err == nil {
+			size[wp.Path] = fi.Size()
+		}
+	}
+	sort.SliceStable(wallpapers, func(i, j int) bool {
+		return size[wallpapers[i].Path] > size[wallpapers[j].Path]
+	})
+}
+
+// sortByLastSet orders wallpapers by when they were last set, never-set
+// ones first, then the longest ago.
+func sortByLastSet(wallpapers []api.Wallpaper, historyFile string) {
+	entries, _ := wallpaper.ReadHistory(historyFile)
+	last := make(map[string]time.Time, len(entries))
+	for _, e := range entries {
+		if e.Time.After(last[e.Path]) {
+			last[e.Path] = e.Time
+		}
+	}
+	sort.SliceStable(wallpapers, func(i, j int) bool {
+		return last[wallpapers[i].Path].Before(last[wallpapers[j].Path])
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runSet handles `vista set`: a generated solid colour or two-colour
+// gradient at the screen resolution, set like any other wallpaper.
+func runSet(args []string, o ui.Options) error {
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	colorFlag := fs.String("color", "", "colour #rrggbb, or two comma-separated for a gradient")
+	sizeFlag := fs.String("size", "", "image size WxH (default: screen resolution)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *colorFlag == "" {
+		return errors.New(i18n.T("set needs --color '#rrggbb' or --color '#top,#bottom'"))
+	}
+
+	parts := strings.Split(*colorFlag, ",")
+	if len(parts) > 2 {
+		return errors.New(i18n.T("set takes at most two colours"))
+	}
+	from, err := wallpaper.ParseColor(parts[0])
+	if err != nil {
+		return err
+	}
+	to := from
+	if len(parts) == 2 {
+		if to, err = wallpaper.ParseColor(parts[1]); err != nil {
+			return err
+		}
+	}
+
+	w, h := wallpaper.ScreenSize()
+	if *sizeFlag != "" {
+		if _, err := fmt.Sscanf(*sizeFlag, "%dx%d", &w, &h); err != nil || w <= 0 || h <= 0 {
+			return fmt.Errorf("invalid size %q: use WxH", *sizeFlag)
+		}
+	}
+
+	path, err := wallpaper.Generate(from, to, w, h, filepath.Join(config.CacheDir(), "generated"))
+	if err != nil {
+		return err
+	}
+	return wallpaper.Set(path, wallpaper.Info{}, setOptions(o))
+}
+
+// setOptions is how the commands outside the grid apply wallpapers, using
+// the same script, transition, overlays and history as the grid.
+func setOptions(o ui.Options) wallpaper.Options {
+	return wallpaper.Options{
+		Script:      wallpaper.Script{Command: o.Script, Shell: o.ScriptShell, Timeout: o.ScriptTimeout},
+		Transition:  o.Transition,
+		Overlays:    o.Overlays,
+		OverlayDir:  o.OverlayDir,
+		CurrentFile: o.CurrentFile,
+		HistoryFile: o.HistoryFile,
+	}
+}
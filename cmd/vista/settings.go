@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/api"
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+// runSettings fetches the Wallhaven account settings for the API key, shows
+// them and offers to copy them into the local config file. A locked purity
+// is left alone.
+func runSettings(client *api.Client, purityLocked bool) error {
+	s, err := client.Settings()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.T("Wallhaven account settings:"))
+	fmt.Printf("  purity:       %s\n", strings.Join(s.Purity, ","))
+	fmt.Printf("  categories:   %s\n", strings.Join(s.Categories, ","))
+	fmt.Printf("  ratios:       %s\n", strings.Join(s.AspectRatios, ","))
+	fmt.Printf("  resolutions:  %s\n", strings.Join(s.Resolutions, ","))
+
+	values := map[string]any{}
+	if len(s.Purity) > 0 && !purityLocked {
+		values["purity"] = s.Purity
+	}
+	if len(s.Categories) > 0 {
+		values["categories"] = s.Categories
+	}
+	if len(s.AspectRatios) > 0 {
+		values["ratios"] = s.AspectRatios
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	fmt.Printf(i18n.T("Write purity, categories and ratios to %s? [y/N] "), config.Path())
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return nil
+	}
+	if err := config.SetValues(values); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	fmt.Println(i18n.T("Config updated."))
+	return nil
+}
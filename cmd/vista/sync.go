@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/davenicholson-xyz/vista/internal/config"
+	"github.com/davenicholson-xyz/vista/internal/dirsync"
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runSync handles `vista push [remote]` and `vista pull [remote]`: it copies
+// the wallpapers in the download directory that the other machine lacks, or
+// holds an older version of, over ssh, then indexes them into the library
+// on the receiving side and merges in the sender's ratings, favorites and
+// library metadata. The remote is [user@]host[:path], by default
+// sync_remote from the config; without a path the other machine's own
+// download_dir is used. vista must be installed there too. Nothing is
+// deleted on either side.
+func runSync(cmd string, args []string, cfg *config.Config, stores store.Stores, o ui.Options, verbose bool) error {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "list what would be copied without copying it")
+	remoteVista := fs.String("vista", "vista", "vista command on the remote machine")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	remote := cfg.SyncRemote
+	if fs.NArg() > 0 {
+		remote = fs.Arg(0)
+	}
+	if remote == "" {
+		return fmt.Errorf(i18n.T("%s needs a remote: [user@]host[:path], or sync_remote in the config"), cmd)
+	}
+	target, err := dirsync.ParseTarget(remote)
+	if err != nil {
+		return err
+	}
+	if o.NoExec {
+		return fmt.Errorf(i18n.T("--no-exec: %s needs ssh"), cmd)
+	}
+
+	c, err := dirsync.Dial(context.Background(), target, *remoteVista)
+	if err != nil {
+		return err
+	}
+	remoteFiles, err := c.List()
+	if err != nil {
+		c.Close() //nolint:errcheck
+		return fmt.Errorf("%s: %w", target, err)
+	}
+	localFiles, err := dirsync.Manifest(o.DownloadDir)
+	if err != nil {
+		c.Close() //nolint:errcheck
+		return err
+	}
+
+	src, dst := localFiles, remoteFiles
+	if cmd == "pull" {
+		src, dst = remoteFiles, localFiles
+	}
+	todo := dirsync.Diff(src, dst)
+	failed := 0
+	for _, f := range todo {
+		if verbose || *dryRun {
+			fmt.Println(f.Name)
+		}
+		if *dryRun {
+			continue
+		}
+		var err error
+		if cmd == "push" {
+			err = c.Put(o.DownloadDir, f)
+		} else {
+			_, err = c.Get(o.DownloadDir, f)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", f.Name, err)
+			failed++
+		}
+	}
+	// The stores follow the files: a push merges ours there, a pull
+	// fetches theirs to merge here once the session is closed.
+	var index store.Portable
+	switch {
+	case *dryRun:
+	case cmd == "push":
+		if index, err = stores.Export(o.DownloadDir); err == nil {
+			err = c.Merge(index)
+		}
+	default:
+		index, err = c.Index()
+	}
+	if cerr := c.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", target, err)
+	}
+
+	if cmd == "pull" && !*dryRun {
+		if err := stores.Merge(o.DownloadDir, index); err != nil {
+			return err
+		}
+	}
+	if !*dryRun {
+		fmt.Printf(i18n.T("Copied %d wallpapers, %d already up to date")+"\n", len(todo)-failed, len(src)-len(todo))
+	}
+	if failed > 0 {
+		return fmt.Errorf(i18n.T("%d files could not be copied"), failed)
+	}
+	return nil
+}
+
+// runSyncServe handles `vista sync-serve [dir]`, the far end of push and
+// pull that they start over ssh. It speaks the sync protocol on standard
+// input and output, so it must print nothing else there, and indexes any
+// wallpapers it receives into the library.
+func runSyncServe(args []string, cfg *config.Config, stores store.Stores) error {
+	dir := cfg.ResolvedDownloadDir()
+	if len(args) > 0 {
+		dir = config.ExpandPath(args[0])
+	}
+	received, err := dirsync.Serve(os.Stdin, os.Stdout, dir, stores)
+	if len(received) > 0 {
+		if _, ierr := stores.Library.Import(dir); err == nil {
+			err = ierr
+		}
+	}
+	return err
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/ui"
+)
+
+// runUndo handles `vista undo`: it restores the wallpaper set before the
+// last change. Repeating it steps further back through the history.
+func runUndo(o ui.Options) error {
+	e, err := wallpaper.Undo(setOptions(o))
+	if errors.Is(err, wallpaper.ErrNothingToUndo) {
+		return errors.New(i18n.T("nothing to undo"))
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf(i18n.T("Restored %s")+"\n", e.Path)
+	return nil
+}
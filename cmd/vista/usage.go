@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+type usageEntry struct {
+	name string
+	desc string
+}
+
+var commandHelp = []usageEntry{
+	{"search,  s  <query>", "search by keyword"},
+	{"top,     t  [query]", "top-rated wallpapers"},
+	{"hot,     h  [query]", "trending wallpapers"},
+	{"new,     n  [query]", "newest wallpapers"},
+	{"random,  r  [query]", "random wallpapers"},
+	{"tag,     tg <tag...>", "wallpapers with every tag (-tag excludes, a number is a tag ID)"},
+	{"user <username>", "wallpapers uploaded by a Wallhaven user, newest first"},
+	{"discover [name]", "pick from starter searches: minimal, nature, space, anime, dark, city, abstract"},
+	{"reddit <subreddit>", "image posts from a subreddit, --sort hot, new, top or rising, --time for top"},
+	{"apod", "NASA's picture of the day, or --date, --from/--to a range, --random n"},
+	{"local,   lo", "browse downloaded and imported wallpapers offline"},
+	{"review [--sort set]", "clean up downloads, largest (or least recently set) first: space keeps, d deletes"},
+	{"history, hi", "browse wallpapers you have set, most recent first"},
+	{"history list", "print every change with its time, ID and page (--since date)"},
+	{"library import <dir>", "index an existing folder (--lookup confirms Wallhaven IDs)"},
+	{"library list", "list imported wallpapers (--sort rating, --min-rating n)"},
+	{"library rate <id>", "rate a wallpaper or file 0-5 (0 clears), then an optional note"},
+	{"lookup <file>", "find a local image's Wallhaven page by file name or image match"},
+	{"id <id|url>", "open one Wallhaven wallpaper by ID or link; enter sets it"},
+	{"credits", "print the photographer credit for the current wallpaper"},
+	{"favs,    fa", "browse wallpapers starred with f"},
+	{"play [--once] [name]", "cycle through a playlist (a in the grid queues to \"queue\")"},
+	{"daily", "set the wallpaper of the day for --query; the same one all day"},
+	{"daemon", "set a wallpaper every --interval (30m), matching --query, picked by --strategy"},
+	{"ctl next", "make the running daemon change wallpaper now"},
+	{"board", "full-screen photo frame of --query, every --interval (5m), within --hours"},
+	{"export-list <query>", "download results and print paths, an m3u or a feh/swww/swaybg script (--format)"},
+	{"export-html <query>", "write a shareable HTML gallery of the results to --out (gallery.html)"},
+	{"collections", "list your Wallhaven collections (needs --apikey)"},
+	{"settings", "copy your Wallhaven account settings into the config"},
+	{"undo", "restore the previous wallpaper; repeat to step further back"},
+	{"set --color <hex>", "set a solid colour; two comma-separated colours make a gradient"},
+	{"replay <file.cast>", "play back a grid session saved with --record"},
+	{"push [host:path]", "copy new downloads to another machine over ssh (default sync_remote)"},
+	{"pull [host:path]", "copy new wallpapers from another machine into download_dir"},
+}
+
+var flagHelp = []usageEntry{
+	{"--apikey", "Wallhaven API key"},
+	{"--purity", "comma-separated: sfw,sketchy,nsfw"},
+	{"--categories", "comma-separated: general,anime,people"},
+	{"--min-resolution", "minimum resolution e.g. 1920x1080"},
+	{"--resolutions", "only these exact resolutions e.g. 2560x1440,3840x2160"},
+	{"--ratios", "comma-separated aspect ratios e.g. 16x9,16x10, or landscape/portrait"},
+	{"--top-range", "toplist window for top: 1d, 3d, 1w, 1M, 3M, 6M or 1y"},
+	{"--colors", "dominant colour from Wallhaven's palette, e.g. 0066cc or #cc0000"},
+	{"--download-dir", "directory to save wallpapers"},
+	{"--script", "script to run instead of setting the wallpaper; {path}, {id}, {url}, {monitor} are substituted"},
+	{"--confirm-quit", "ask before quitting while downloads are in flight"},
+	{"--idle-timeout", "exit after N minutes without input (0 disables)"},
+	{"--apply first|random", "set a result without opening the grid, then exit (or least-recent, top-unseen, top-rated, palette)"},
+	{"--source fixture:<dir>", "serve results from page-N.json files (e.g. saved --json output) instead of Wallhaven"},
+	{"--no-exec", "never run external programs: built-in renderers and wallpaper setting only, no scripts, swww or browser"},
+	{"--json", "print the first page of results with its meta as JSON and exit"},
+	{"--events json", "with --apply or daemon, print search_started, downloaded, set and error events as JSON lines"},
+	{"--stay", "Enter sets the wallpaper without leaving the grid"},
+	{"--slideshow", "seconds per wallpaper in the 'p' slideshow preview"},
+	{"--thumb-size", "grid thumbnail source: small, large or original"},
+	{"--transition", "swww transition type, e.g. fade, wipe, grow"},
+	{"--transition-duration", "transition length in seconds"},
+	{"--transition-pos", "transition origin, e.g. center or 0.8,0.9"},
+	{"--renderer", "image renderer: auto, chafa, kitty, sixel, iterm, ueberzug"},
+	{"--ascii", "plain ASCII UI (no box drawing or block characters)"},
+	{"--browse-only", "browse only: never set or delete wallpapers or run scripts"},
+	{"--log", "append diagnostics (render failures etc.) to this file"},
+	{"--record", "save the grid session as an asciinema cast, for bug reports"},
+	{"--verbose, -v", "print progress messages"},
+}
+
+// usage renders the help text in the current locale.
+func usage() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s vista [flags] <command> [query]\n\n", i18n.T("Usage:"))
+	fmt.Fprintf(&b, "%s\n", i18n.T("Commands:"))
+	for _, c := range commandHelp {
+		fmt.Fprintf(&b, "  %-20s %s\n", c.name, i18n.T(c.desc))
+	}
+	fmt.Fprintf(&b, "\n  %s\n", i18n.T("Queries accept +tag (required), -tag (excluded) and id:N (exact tag ID)."))
+	fmt.Fprintf(&b, "\n%s\n", i18n.T("Flags:"))
+	for _, f := range flagHelp {
+		fmt.Fprintf(&b, "  %-22s %s\n", f.name, i18n.T(f.desc))
+	}
+	fmt.Fprintf(&b, "\n%s\n", i18n.T("Flags override values from ~/.config/vista/config.yaml."))
+	return b.String()
+}
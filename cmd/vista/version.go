@@ -0,0 +1,23 @@
+package main
+
+import "runtime/debug"
+
+// version is set for release builds with -ldflags "-X main.version=v1.2.3".
+var version = ""
+
+// buildVersion returns version, or the module version go install recorded,
+// or "dev" for a local build.
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// userAgent is the User-Agent vista sends unless the config sets one.
+func userAgent() string {
+	return "vista/" + buildVersion() + " (+https://github.com/davenicholson-xyz/vista)"
+}
@@ -4,8 +4,7 @@ go 1.24.0
 
 require (
 	github.com/davenicholson-xyz/go-setwallpaper v0.1.0
+	golang.org/x/sys v0.41.0
 	golang.org/x/term v0.40.0
 	gopkg.in/yaml.v3 v3.0.1
 )
-
-require golang.org/x/sys v0.41.0 // indirect
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// searchColors is the fixed palette the colors search parameter accepts.
+var searchColors = []string{
+	"660000", "990000", "cc0000", "cc3333", "ea4c88", "993399", "663399",
+	"333399", "0066cc", "0099cc", "66cccc", "77cc33", "669900", "336600",
+	"666600", "999900", "cccc33", "ffff00", "ffcc33", "ff9900", "ff6600",
+	"cc6633", "996633", "663300", "000000", "999999", "cccccc", "ffffff",
+	"424153",
+}
+
+// NormalizeColor checks that hex, with or without a leading #, is one of
+// the palette colours Wallhaven filters by and returns it in the form the
+// API expects, e.g. "#CC0000" becomes "cc0000".
+func NormalizeColor(hex string) (string, error) {
+	c := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hex), "#"))
+	for _, sc := range searchColors {
+		if c == sc {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("colour %q is not in Wallhaven's palette: use one of %s", hex, strings.Join(searchColors, " "))
+}
+
+// DominantColor returns the search palette colour most of img is closest
+// to, sampling a grid of pixels.
+func DominantColor(img image.Image) string {
+	counts := make([]int, len(searchColors))
+	b := img.Bounds()
+	step := max(min(b.Dx(), b.Dy())/64, 1)
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			counts[nearestColor(int(r>>8), int(g>>8), int(bl>>8))]++
+		}
+	}
+	best := 0
+	for i, c := range counts {
+		if c > counts[best] {
+			best = i
+		}
+	}
+	return searchColors[best]
+}
+
+func nearestColor(r, g, b int) int {
+	best, bestDist := 0, -1
+	for i, hex := range searchColors {
+		v, _ := strconv.ParseUint(hex, 16, 32)
+		dr, dg, db := r-int(v>>16), g-int(v>>8&0xff), b-int(v&0xff)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
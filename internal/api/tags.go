@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TagInfo is a tag as returned by /tag/{id}.
+type TagInfo struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Purity   string `json:"purity"`
+}
+
+// Tag looks up a tag by its numeric ID.
+func (c *Client) Tag(id int) (TagInfo, error) {
+	var result struct {
+		Data TagInfo `json:"data"`
+	}
+	if err := c.getJSON(context.Background(), fmt.Sprintf("%s/tag/%d", apiRoot, id), url.Values{}, &result); err != nil {
+		return TagInfo{}, fmt.Errorf("tag %d: %w", id, err)
+	}
+	return result.Data, nil
+}
+
+// TagQuery builds a search query requiring every tag in tags. A plain name
+// becomes +name, a -name excludes the tag and a number searches for that
+// exact tag ID. Wallhaven tags containing spaces can only be matched by ID.
+func TagQuery(tags []string) (string, error) {
+	if len(tags) == 0 {
+		return "", fmt.Errorf("no tag given")
+	}
+	terms := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if _, err := strconv.Atoi(t); err == nil {
+			if len(tags) > 1 {
+				return "", fmt.Errorf("tag ID %s can't be combined with other tags", t)
+			}
+			terms = append(terms, "id:"+t)
+			continue
+		}
+		if strings.ContainsAny(t, " \t") {
+			return "", fmt.Errorf("tag %q contains a space: search by its numeric ID instead", t)
+		}
+		if !strings.HasPrefix(t, "-") && !strings.HasPrefix(t, "+") {
+			t = "+" + t
+		}
+		terms = append(terms, t)
+	}
+	q := strings.Join(terms, " ")
+	return q, ValidateQuery(q)
+}
+
+// ValidateQuery checks the +tag, -tag and id:N terms of a search query so
+// malformed syntax is reported instead of silently matching nothing.
+func ValidateQuery(q string) error {
+	ids := 0
+	for _, term := range strings.Fields(q) {
+		switch {
+		case term == "+" || term == "-":
+			return fmt.Errorf("%q needs a tag name directly after it", term)
+		case strings.HasPrefix(term, "+-"), strings.HasPrefix(term, "-+"),
+			strings.HasPrefix(term, "++"), strings.HasPrefix(term, "--"):
+			return fmt.Errorf("%q: use a single + or - before a tag", term)
+		case strings.HasPrefix(term, "id:"):
+			if _, err := strconv.Atoi(term[3:]); err != nil {
+				return fmt.Errorf("%q: id: takes a numeric tag ID", term)
+			}
+			ids++
+		}
+	}
+	if ids > 0 && len(strings.Fields(q)) > 1 {
+		return fmt.Errorf("id: searches can't be combined with other terms")
+	}
+	return nil
+}
@@ -1,33 +1,32 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
-)
-
-const baseURL = "https://wallhaven.cc/api/v1/search"
+	"path"
+	"slices"
+	"strings"
 
-type Thumbs struct {
-	Large    string `json:"large"`
-	Original string `json:"original"`
-	Small    string `json:"small"`
-}
+	"github.com/davenicholson-xyz/vista/internal/retry"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
 
-type Wallpaper struct {
-	ID         string `json:"id"`
-	URL        string `json:"url"`
-	Path       string `json:"path"`
-	Resolution string `json:"resolution"`
-	Thumbs     Thumbs `json:"thumbs"`
-}
+const (
+	apiRoot = "https://wallhaven.cc/api/v1"
+	baseURL = apiRoot + "/search"
+)
 
-type Meta struct {
-	CurrentPage int `json:"current_page"`
-	LastPage    int `json:"last_page"`
-	Total       int `json:"total"`
-}
+// The Wallhaven response shapes are shared with the grid via pkg/provider.
+type (
+	Tag       = provider.Tag
+	Thumbs    = provider.Thumbs
+	Wallpaper = provider.Wallpaper
+	Details   = provider.Details
+	Meta      = provider.Meta
+)
 
 type searchResponse struct {
 	Data []Wallpaper `json:"data"`
@@ -39,6 +38,29 @@ type searchResponse struct {
 type SearchOptions struct {
 	Query   string
 	Sorting string
+	// Colors and Resolutions narrow a single search: a hex colour from
+	// Wallhaven's search palette, and comma-separated exact sizes like
+	// "1920x1080".
+	Colors      string
+	Resolutions string
+	// Seed keeps random sorting stable across pages; copy it from the
+	// first page's Meta.
+	Seed string
+	// TopRange is the toplist window, one of TopRanges. It only applies
+	// when Sorting is toplist.
+	TopRange string
+}
+
+// TopRanges are the toplist windows the API accepts, shortest first.
+var TopRanges = []string{"1d", "3d", "1w", "1M", "3M", "6M", "1y"}
+
+// ValidateTopRange returns an error unless r is empty or one of TopRanges.
+// The API is case-sensitive here: 1m is not 1M.
+func ValidateTopRange(r string) error {
+	if r == "" || slices.Contains(TopRanges, r) {
+		return nil
+	}
+	return fmt.Errorf("top range %q: use one of %s", r, strings.Join(TopRanges, ", "))
 }
 
 type Client struct {
@@ -48,9 +70,27 @@ type Client struct {
 	Categories    string
 	MinResolution string
 	Ratios        string
+	// Colors and Resolutions are the default colour and exact size
+	// filters, used when a search sets none.
+	Colors      string
+	Resolutions string
+	// TopRange is the default toplist window, used when a toplist search
+	// sets none.
+	TopRange string
+	// UserAgent is sent with every request; empty leaves Go's default.
+	UserAgent string
+	// Retries is how many times a request that fails transiently, with a
+	// network error, 429 or 5xx, is repeated with backoff.
+	Retries int
 }
 
 func (c *Client) SearchPage(opts SearchOptions, page int) ([]Wallpaper, Meta, error) {
+	return c.SearchPageContext(context.Background(), opts, page)
+}
+
+// SearchPageContext is SearchPage with a context that cancels the request
+// and any retries.
+func (c *Client) SearchPageContext(ctx context.Context, opts SearchOptions, page int) ([]Wallpaper, Meta, error) {
 	params := url.Values{}
 	if opts.Query != "" {
 		params.Set("q", opts.Query)
@@ -58,6 +98,26 @@ func (c *Client) SearchPage(opts SearchOptions, page int) ([]Wallpaper, Meta, er
 	if opts.Sorting != "" {
 		params.Set("sorting", opts.Sorting)
 	}
+	if opts.Colors != "" {
+		params.Set("colors", opts.Colors)
+	} else if c.Colors != "" {
+		params.Set("colors", c.Colors)
+	}
+	if opts.Resolutions != "" {
+		params.Set("resolutions", opts.Resolutions)
+	} else if c.Resolutions != "" {
+		params.Set("resolutions", c.Resolutions)
+	}
+	if opts.Sorting == "toplist" {
+		if opts.TopRange != "" {
+			params.Set("topRange", opts.TopRange)
+		} else if c.TopRange != "" {
+			params.Set("topRange", c.TopRange)
+		}
+	}
+	if opts.Seed != "" {
+		params.Set("seed", opts.Seed)
+	}
 	params.Set("page", fmt.Sprintf("%d", page))
 	if c.Purity != "" {
 		params.Set("purity", c.Purity)
@@ -71,34 +131,176 @@ func (c *Client) SearchPage(opts SearchOptions, page int) ([]Wallpaper, Meta, er
 	if c.Ratios != "" {
 		params.Set("ratios", c.Ratios)
 	}
+
+	var result searchResponse
+	if err := c.getJSON(ctx, baseURL, params, &result); err != nil {
+		return nil, Meta{}, err
+	}
+
+	return result.Data, result.Meta, nil
+}
+
+// Search binds a Client to a fixed set of SearchOptions so it can be used
+// as a provider.Provider by the grid.
+type Search struct {
+	Client *Client
+	Opts   SearchOptions
+}
+
+// Page fetches one page. The seed of a random search is kept from the first
+// response so later pages don't repeat or skip wallpapers.
+func (s *Search) Page(page int) ([]Wallpaper, Meta, error) {
+	return s.PageContext(context.Background(), page)
+}
+
+// PageContext is Page with a context.
+func (s *Search) PageContext(ctx context.Context, page int) ([]Wallpaper, Meta, error) {
+	wallpapers, meta, err := s.Client.SearchPageContext(ctx, s.Opts, page)
+	if err == nil && s.Opts.Seed == "" {
+		s.Opts.Seed = meta.Seed
+	}
+	return wallpapers, meta, err
+}
+
+var _ provider.ContextProvider = (*Search)(nil)
+
+// GetWallpaper fetches a single wallpaper with its full metadata from
+// /w/{id}. id may also be a downloaded file name such as
+// wallhaven-8xkxjo.jpg.
+func (c *Client) GetWallpaper(id string) (Details, error) {
+	return c.GetWallpaperContext(context.Background(), id)
+}
+
+// GetWallpaperContext is GetWallpaper with a context.
+func (c *Client) GetWallpaperContext(ctx context.Context, id string) (Details, error) {
+	if name, ok := strings.CutPrefix(id, "wallhaven-"); ok {
+		id = strings.TrimSuffix(name, path.Ext(name))
+	}
+	var result struct {
+		Data struct {
+			Wallpaper
+			Uploader struct {
+				Username string `json:"username"`
+			} `json:"uploader"`
+			Views     int      `json:"views"`
+			Favorites int      `json:"favorites"`
+			Colors    []string `json:"colors"`
+			FileSize  int64    `json:"file_size"`
+			FileType  string   `json:"file_type"`
+			Category  string   `json:"category"`
+			Purity    string   `json:"purity"`
+			Source    string   `json:"source"`
+			CreatedAt string   `json:"created_at"`
+		} `json:"data"`
+	}
+	if err := c.getJSON(ctx, apiRoot+"/w/"+url.PathEscape(id), url.Values{}, &result); err != nil {
+		return Details{}, fmt.Errorf("wallpaper %s: %w", id, err)
+	}
+	d := result.Data
+	return Details{
+		Wallpaper: d.Wallpaper,
+		Uploader:  d.Uploader.Username,
+		Views:     d.Views,
+		Favorites: d.Favorites,
+		Colors:    d.Colors,
+		FileSize:  d.FileSize,
+		FileType:  d.FileType,
+		Category:  d.Category,
+		Purity:    d.Purity,
+		Source:    d.Source,
+		CreatedAt: d.CreatedAt,
+	}, nil
+}
+
+var _ provider.ContextDetailer = (*Client)(nil)
+
+// Collection is one of the authenticated user's Wallhaven collections.
+type Collection struct {
+	ID     int    `json:"id"`
+	Label  string `json:"label"`
+	Public int    `json:"public"`
+	Count  int    `json:"count"`
+}
+
+// Collections lists the collections owned by the API key's account.
+// The v1 API only exposes collections read-only; adding or removing
+// wallpapers has to be done on the website.
+func (c *Client) Collections() ([]Collection, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("an API key is required to list collections")
+	}
+	var result struct {
+		Data []Collection `json:"data"`
+	}
+	if err := c.getJSON(context.Background(), apiRoot+"/collections", url.Values{}, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// getJSON performs an authenticated GET against endpoint and decodes the
+// JSON body into v.
+func (c *Client) getJSON(ctx context.Context, endpoint string, params url.Values, v any) error {
 	if c.APIKey != "" {
 		params.Set("apikey", c.APIKey)
 	}
 
-	reqURL := baseURL + "?" + params.Encode()
+	reqURL := endpoint
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, Meta{}, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("creating request: %w", err)
 	}
 	if c.APIKey != "" {
 		req.Header.Set("X-API-Key", c.APIKey)
 	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := retry.Do(http.DefaultClient, req, c.Retries)
 	if err != nil {
-		return nil, Meta{}, fmt.Errorf("executing request: %w", err)
+		return fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, Meta{}, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
-	var result searchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, Meta{}, fmt.Errorf("decoding response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
 	}
+	return nil
+}
 
-	return result.Data, result.Meta, nil
+// Settings are the account preferences returned by /settings.
+type Settings struct {
+	Purity       []string `json:"purity"`
+	Categories   []string `json:"categories"`
+	Resolutions  []string `json:"resolutions"`
+	AspectRatios []string `json:"aspect_ratios"`
+	ToplistRange string   `json:"toplist_range"`
+}
+
+// Settings fetches the account settings of the API key's owner.
+func (c *Client) Settings() (Settings, error) {
+	return c.SettingsContext(context.Background())
+}
+
+// SettingsContext is Settings with a context.
+func (c *Client) SettingsContext(ctx context.Context) (Settings, error) {
+	if c.APIKey == "" {
+		return Settings{}, fmt.Errorf("an API key is required to fetch account settings")
+	}
+	var result struct {
+		Data Settings `json:"data"`
+	}
+	if err := c.getJSON(ctx, apiRoot+"/settings", url.Values{}, &result); err != nil {
+		return Settings{}, err
+	}
+	return result.Data, nil
 }
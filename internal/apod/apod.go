@@ -0,0 +1,230 @@
+// Package apod offers NASA's Astronomy Picture of the Day as wallpapers,
+// through the APOD API at api.nasa.gov: one day's picture, a range of
+// days or random ones. Days whose picture is a video are skipped.
+package apod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/retry"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// DemoKey is NASA's shared key, limited to a few requests an hour.
+const DemoKey = "DEMO_KEY"
+
+// First is the day of the first picture.
+var First = time.Date(1995, 6, 16, 0, 0, 0, 0, time.UTC)
+
+// today is the latest day with a picture. APOD dates follow US Eastern
+// time, so east of it the local date can be a day ahead of the archive's,
+// and the API rejects future dates.
+func today() time.Time {
+	now := time.Now()
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		now = now.In(loc)
+	} else {
+		now = now.UTC().Add(-5 * time.Hour) // EST when tzdata is missing
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// pageDays is how many days of a range one page covers.
+const pageDays = 30
+
+// maxCount is the most random pictures the API returns at once.
+const maxCount = 100
+
+// endpoint is the APOD API.
+var endpoint = "https://api.nasa.gov/planetary/apod"
+
+// Source is a provider.ContextProvider of APOD pictures. With Count set it
+// serves that many random pictures a page, without end; with From set, the
+// days From to To, newest first; otherwise only Date's picture.
+type Source struct {
+	// APIKey is an api.nasa.gov key; empty uses DemoKey.
+	APIKey string
+	// Date is the day to show; zero means today.
+	Date time.Time
+	// From and To bound a range of days; a zero To means today.
+	From, To time.Time
+	// Count asks for random pictures instead.
+	Count int
+	// UserAgent is sent with every request.
+	UserAgent string
+	// Retries is how many times a request that fails transiently is
+	// repeated.
+	Retries int
+}
+
+// Validate checks that the dates are within the archive and only one of a
+// day, a range and random pictures is asked for.
+func (s *Source) Validate() error {
+	switch {
+	case s.Count != 0 && (!s.From.IsZero() || !s.Date.IsZero()),
+		!s.From.IsZero() && !s.Date.IsZero():
+		return errors.New("choose one of a date, a range or random pictures")
+	case s.Count < 0 || s.Count > maxCount:
+		return fmt.Errorf("random count %d is out of range 1-%d", s.Count, maxCount)
+	case !s.To.IsZero() && s.From.IsZero():
+		return errors.New("a range needs a start date")
+	case !s.To.IsZero() && s.To.Before(s.From):
+		return errors.New("the range ends before it starts")
+	}
+	for _, d := range []time.Time{s.Date, s.From} {
+		if !d.IsZero() && d.Before(First) {
+			return fmt.Errorf("APOD starts on %s", First.Format(time.DateOnly))
+		}
+		if t := today(); d.After(t) {
+			return fmt.Errorf("there is no picture after %s yet", t.Format(time.DateOnly))
+		}
+	}
+	return nil
+}
+
+// Page returns page of the pictures.
+func (s *Source) Page(page int) ([]provider.Wallpaper, provider.Meta, error) {
+	return s.PageContext(context.Background(), page)
+}
+
+// PageContext is Page with a context.
+func (s *Source) PageContext(ctx context.Context, page int) ([]provider.Wallpaper, provider.Meta, error) {
+	params := url.Values{}
+	meta := provider.Meta{CurrentPage: page, LastPage: 1}
+	single := false // the API answers a lone date with an object, not a list
+	switch {
+	case s.Count > 0:
+		params.Set("count", fmt.Sprint(s.Count))
+		meta.LastPage = page + 1
+		meta.PerPage = s.Count
+	case !s.From.IsZero():
+		to := s.To
+		if t := today(); to.IsZero() || to.After(t) {
+			to = t
+		}
+		days := int(to.Sub(s.From).Hours()/24) + 1
+		meta.LastPage = (days + pageDays - 1) / pageDays
+		meta.PerPage = pageDays
+		meta.Total = days
+		if page > meta.LastPage {
+			return nil, meta, nil
+		}
+		end := to.AddDate(0, 0, -pageDays*(page-1))
+		start := end.AddDate(0, 0, -(pageDays - 1))
+		if start.Before(s.From) {
+			start = s.From
+		}
+		params.Set("start_date", start.Format(time.DateOnly))
+		params.Set("end_date", end.Format(time.DateOnly))
+	default:
+		if page > 1 {
+			return nil, meta, nil
+		}
+		single = true
+		if !s.Date.IsZero() {
+			params.Set("date", s.Date.Format(time.DateOnly))
+		}
+	}
+
+	var pictures []picture
+	if single {
+		var p picture
+		if err := s.getJSON(ctx, params, &p); err != nil {
+			return nil, provider.Meta{}, err
+		}
+		pictures = []picture{p}
+	} else if err := s.getJSON(ctx, params, &pictures); err != nil {
+		return nil, provider.Meta{}, err
+	}
+
+	var wallpapers []provider.Wallpaper
+	for i := len(pictures) - 1; i >= 0; i-- { // ranges come oldest first
+		if wp, ok := pictures[i].wallpaper(); ok {
+			wallpapers = append(wallpapers, wp)
+		}
+	}
+	return wallpapers, meta, nil
+}
+
+// picture is one day of the APOD API.
+type picture struct {
+	Date      string `json:"date"`
+	Copyright string `json:"copyright"`
+	MediaType string `json:"media_type"`
+	URL       string `json:"url"`
+	HDURL     string `json:"hdurl"`
+}
+
+// wallpaper converts p, reporting false for a video or other non-image.
+func (p picture) wallpaper() (provider.Wallpaper, bool) {
+	if p.MediaType != "image" || p.URL == "" {
+		return provider.Wallpaper{}, false
+	}
+	original := p.HDURL
+	if original == "" {
+		original = p.URL
+	}
+	wp := provider.Wallpaper{
+		ID:     p.Date,
+		Path:   original,
+		Thumbs: provider.Thumbs{Small: p.URL, Large: p.URL, Original: original},
+		Source: "APOD",
+		Author: "NASA",
+	}
+	if d, err := time.Parse(time.DateOnly, p.Date); err == nil {
+		wp.URL = "https://apod.nasa.gov/apod/ap" + d.Format("060102") + ".html"
+	}
+	if c := strings.Join(strings.Fields(p.Copyright), " "); c != "" {
+		wp.Author = c
+	}
+	return wp, true
+}
+
+func (s *Source) getJSON(ctx context.Context, params url.Values, v any) error {
+	key := s.APIKey
+	if key == "" {
+		key = DemoKey
+	}
+	params.Set("api_key", key)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if s.UserAgent != "" {
+		req.Header.Set("User-Agent", s.UserAgent)
+	}
+	resp, err := retry.Do(http.DefaultClient, req, s.Retries)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return errors.New("the NASA API rate limit was reached; set nasa_apikey to your own key")
+	case resp.StatusCode != http.StatusOK:
+		var body struct {
+			Msg   string `json:"msg"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body) //nolint:errcheck
+		if msg := body.Msg + body.Error.Message; msg != "" {
+			return fmt.Errorf("NASA API returned status %d: %s", resp.StatusCode, msg)
+		}
+		return fmt.Errorf("NASA API returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+var _ provider.ContextProvider = (*Source)(nil)
@@ -1,65 +1,262 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/davenicholson-xyz/vista/internal/retry"
 )
 
 type Config struct {
 	APIKey        string   `yaml:"apikey"`
 	Username      string   `yaml:"username"`
+	NasaKey       string   `yaml:"nasa_apikey"` // api.nasa.gov key for apod; default DEMO_KEY
 	Purity        []string `yaml:"purity"`
+	PurityLock    bool     `yaml:"purity_lock"` // pin purity to sfw; see SystemPath
 	Categories    []string `yaml:"categories"`
 	MinResolution string   `yaml:"min_resolution"`
+	Resolutions   []string `yaml:"resolutions"` // exact sizes, e.g. 2560x1440
 	Ratios        []string `yaml:"ratios"`
+	Colors        string   `yaml:"colors"`    // hex from Wallhaven's search palette
+	TopRange      string   `yaml:"top_range"` // toplist window: 1d, 3d, 1w, 1M, 3M, 6M or 1y
 	DownloadDir   string   `yaml:"download_dir"`
 	Script        string   `yaml:"script"`
+	ScriptShell   bool     `yaml:"script_shell"`
+	ScriptTimeout int      `yaml:"script_timeout"`      // seconds
+	Transition    string   `yaml:"transition_type"`     // swww: fade, wipe, grow, …
+	TransDuration float64  `yaml:"transition_duration"` // seconds
+	TransPos      string   `yaml:"transition_pos"`
+	ConfirmQuit   bool     `yaml:"confirm_quit"`
+	IdleTimeout   int      `yaml:"idle_timeout"` // minutes, 0 disables
+	StayOpen      bool     `yaml:"stay_open"`
+	TrySeconds    int      `yaml:"try_seconds"`        // how long 't' waits before reverting
+	SlideInterval int      `yaml:"slideshow_interval"` // seconds
+	ThumbSize     string   `yaml:"thumb_size"`         // small, large or original
+	LogFile       string   `yaml:"log_file"`
+	Renderer      string   `yaml:"renderer"` // auto, chafa, kitty, sixel, iterm, ueberzug
+	EnlargeSel    bool     `yaml:"enlarge_selected"`
+	Gap           int      `yaml:"gap"`
+	CellAspect    string   `yaml:"cell_aspect"`  // thumbnail cell shape, e.g. 16:9 or 9:16; default auto
+	BorderStyle   string   `yaml:"border_style"` // double, single, rounded, heavy, ascii
+	BorderAll     bool     `yaml:"border_unselected"`
+	Selection     string   `yaml:"selection"` // color, inverse, thick, arrow
+	ASCII         bool     `yaml:"ascii"`
+	Workers       int      `yaml:"download_workers"`
+	Prefetch      int      `yaml:"prefetch_pages"`     // pages loaded ahead of the selection
+	MaxPrefetch   int      `yaml:"max_prefetch_pages"` // pages loaded ahead when scrolling fast
+	MaxCacheSize  string   `yaml:"max_cache_size"`     // download_dir cap, e.g. 2GB; empty for none
+	ConnsPerHost  int      `yaml:"conns_per_host"`     // 0: limited by download_workers only
+	UserAgent     string   `yaml:"user_agent"`         // default: vista/<version>
+	Retries       int      `yaml:"retries"`            // transient HTTP failures; 0 disables
+	RepeatWindow  int      `yaml:"repeat_window"`      // recent sets random picks avoid
+	Strategy      string   `yaml:"strategy"`           // daemon picks: random, least-recent, top-unseen, top-rated, palette
+	MinRating     int      `yaml:"min_rating"`         // picks skip wallpapers rated below; unrated ones are kept
+	SyncRemote    string   `yaml:"sync_remote"`        // [user@]host[:path] push and pull use by default
+	Fallback      string   `yaml:"fallback"`           // none, local: what --apply and the daemon use when a search fails
+
+	// Overlays are composited onto every wallpaper before it is set.
+	Overlays []Overlay `yaml:"overlays"`
+	// Playlists are named, ordered wallpaper lists for `vista play`.
+	Playlists map[string]Playlist `yaml:"playlists"`
+	// AccountDefaults takes purity, categories, resolutions, ratios and
+	// top_range from the Wallhaven account of the API key, where this file
+	// doesn't set them. On by default.
+	AccountDefaults bool `yaml:"account_defaults"`
+
+	set map[string]bool // top-level keys present in the file; see IsSet
+}
+
+// Playlist is an ordered list of wallpapers shown in turn by `vista play`.
+type Playlist struct {
+	Interval string         `yaml:"interval"` // default time per item, e.g. "30m"
+	Items    []PlaylistItem `yaml:"items"`
+}
+
+// PlaylistItem is a Wallhaven ID or a local path, with an optional
+// Duration overriding the playlist's interval.
+type PlaylistItem struct {
+	ID       string `yaml:"id"`
+	Path     string `yaml:"path"`
+	Duration string `yaml:"duration"`
+}
+
+// Overlay is an element composited onto wallpapers before they are set.
+type Overlay struct {
+	Type     string  `yaml:"type"` // image, text or calendar
+	Path     string  `yaml:"path"`
+	Position string  `yaml:"position"` // e.g. top-left, center, bottom-right
+	Opacity  float64 `yaml:"opacity"`
+	Scale    int     `yaml:"scale"`
+	Color    string  `yaml:"color"`
+	Margin   int     `yaml:"margin"`
 }
 
 func Load() (*Config, error) {
 	cfg := &Config{
-		Purity:      []string{"sfw"},
-		Categories:  []string{"general", "anime", "people"},
-		DownloadDir: "~/Pictures/wallpapers",
+		Purity:       []string{"sfw"},
+		Categories:   []string{"general", "anime", "people"},
+		DownloadDir:  "~/Pictures/wallpapers",
+		RepeatWindow: 10,
+		MaxPrefetch:  4,
+		Strategy:     "random",
+		Retries:      retry.Default,
+
+		AccountDefaults: true,
 	}
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return cfg, nil
+	err := cfg.read()
+	// The defaults and the purity lock hold even when the file can't be
+	// read, so breaking it can't lift a lock set by the administrator.
+	if len(cfg.Purity) == 0 {
+		cfg.Purity = []string{"sfw"}
+	}
+	if len(cfg.Categories) == 0 {
+		cfg.Categories = []string{"general", "anime", "people"}
+	}
+	if cfg.DownloadDir == "" {
+		cfg.DownloadDir = "~/Pictures/wallpapers"
 	}
+	if systemLock() {
+		cfg.PurityLock = true
+	}
+	if cfg.PurityLock {
+		cfg.Purity = []string{"sfw"}
+	}
+	return cfg, err
+}
 
-	path := filepath.Join(home, ".config", "vista", "config.yaml")
+// read overlays the user's config file, if there is one, onto c.
+func (c *Config) read() error {
+	path := Path()
+	if path == "" {
+		return nil
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return cfg, nil
+			return nil
 		}
-		return cfg, err
+		return err
 	}
 
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return cfg, err
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return err
 	}
+	var keys map[string]yaml.Node
+	if yaml.Unmarshal(data, &keys) == nil {
+		c.set = make(map[string]bool, len(keys))
+		for k := range keys {
+			c.set[k] = true
+		}
+	}
+	return nil
+}
 
-	if len(cfg.Purity) == 0 {
-		cfg.Purity = []string{"sfw"}
+// IsSet reports whether the config file sets the top-level key, as opposed
+// to it taking its default.
+func (c *Config) IsSet(key string) bool {
+	return c.set[key]
+}
+
+// SystemPath is an administrator's config file. Only its purity_lock is
+// read, and only while the file is owned by root, so a lock set there can't
+// be lifted by the user the way one in their own config can.
+const SystemPath = "/etc/vista/config.yaml"
+
+// systemLock reports whether SystemPath locks purity.
+func systemLock() bool {
+	data, err := os.ReadFile(SystemPath)
+	if err != nil || !ownedByRoot(SystemPath) {
+		return false
 	}
-	if len(cfg.Categories) == 0 {
-		cfg.Categories = []string{"general", "anime", "people"}
+	var sys struct {
+		PurityLock bool `yaml:"purity_lock"`
 	}
-	if cfg.DownloadDir == "" {
-		cfg.DownloadDir = "~/Pictures/wallpapers"
+	return yaml.Unmarshal(data, &sys) == nil && sys.PurityLock
+}
+
+// Path returns the location of the config file, or "" if the home
+// directory can't be determined.
+func Path() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "vista", "config.yaml")
+}
+
+// SetValues writes the given top-level keys into the config file, creating
+// it if needed. Existing keys, ordering and comments are preserved.
+func SetValues(values map[string]any) error {
+	path := Path()
+	if path == "" {
+		return fmt.Errorf("cannot locate home directory")
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", path)
 	}
 
-	return cfg, nil
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var val yaml.Node
+		if err := val.Encode(values[key]); err != nil {
+			return err
+		}
+		found := false
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == key {
+				root.Content[i+1] = &val
+				found = true
+				break
+			}
+		}
+		if !found {
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &val)
+		}
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
 }
 
 // PurityParam converts the human-readable purity list into the 3-bit string
 // the Wallhaven API expects: position 0 = sfw, 1 = sketchy, 2 = nsfw.
 func (c *Config) PurityParam() string {
+	if c.PurityLock {
+		return "100"
+	}
 	bits := [3]byte{'0', '0', '0'}
 	for _, p := range c.Purity {
 		switch p {
@@ -91,18 +288,144 @@ func (c *Config) CategoriesParam() string {
 	return string(bits[:])
 }
 
-// RatiosParam returns the ratios as a comma-separated string for the API.
-func (c *Config) RatiosParam() string {
-	return strings.Join(c.Ratios, ",")
+// RatiosParam returns the ratios as a comma-separated string for the API,
+// or an error naming the first that is neither WxH nor one of the API's
+// landscape and portrait shorthands, which match every wide or tall ratio.
+func (c *Config) RatiosParam() (string, error) {
+	ratios := make([]string, len(c.Ratios))
+	for i, r := range c.Ratios {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if r != "landscape" && r != "portrait" {
+			w, h, ok := strings.Cut(r, "x")
+			if !ok || !digits(w) || !digits(h) {
+				return "", fmt.Errorf("ratio %q: use WIDTHxHEIGHT, e.g. 16x9, or landscape or portrait", c.Ratios[i])
+			}
+		}
+		ratios[i] = r
+	}
+	return strings.Join(ratios, ","), nil
+}
+
+// ResolutionsParam returns the exact resolutions as a comma-separated
+// string for the API, or an error naming the first that isn't WxH.
+func (c *Config) ResolutionsParam() (string, error) {
+	for _, r := range c.Resolutions {
+		w, h, ok := strings.Cut(r, "x")
+		if !ok || !digits(w) || !digits(h) {
+			return "", fmt.Errorf("resolution %q: use WIDTHxHEIGHT, e.g. 2560x1440", r)
+		}
+	}
+	return strings.Join(c.Resolutions, ","), nil
 }
 
-func (c *Config) ResolvedDownloadDir() string {
-	if len(c.DownloadDir) >= 2 && c.DownloadDir[:2] == "~/" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return c.DownloadDir
+// MaxCacheBytes returns max_cache_size in bytes: a number with an optional
+// KB, MB, GB or TB suffix (powers of 1024), e.g. "1.5GB". It returns 0, no
+// cap, for an empty value.
+func (c *Config) MaxCacheBytes() (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(c.MaxCacheSize))
+	if s == "" {
+		return 0, nil
+	}
+	num, mult := strings.TrimSuffix(s, "B"), float64(1)
+	for i, unit := range []string{"K", "M", "G", "T"} {
+		if n, ok := strings.CutSuffix(num, unit); ok {
+			num, mult = strings.TrimSpace(n), float64(int64(1)<<(10*(i+1)))
+			break
 		}
-		return filepath.Join(home, c.DownloadDir[2:])
 	}
-	return c.DownloadDir
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("max_cache_size %q: use a size such as 500MB or 2GB", c.MaxCacheSize)
+	}
+	return int64(v * mult), nil
+}
+
+// CellAspectRatio returns cell_aspect as width over height: "16:9" (or
+// 16x9) gives 1.78, a plain number is taken as is. It returns 0 for an
+// empty value or "auto", which lets the grid pick from the terminal shape.
+func (c *Config) CellAspectRatio() (float64, error) {
+	s := strings.TrimSpace(c.CellAspect)
+	if s == "" || s == "auto" {
+		return 0, nil
+	}
+	bad := fmt.Errorf("cell_aspect %q: use W:H, e.g. 16:9 or 9:16, or auto", c.CellAspect)
+	w, h, ok := strings.Cut(strings.ReplaceAll(s, "x", ":"), ":")
+	if !ok {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v <= 0 {
+			return 0, bad
+		}
+		return v, nil
+	}
+	fw, err1 := strconv.ParseFloat(w, 64)
+	fh, err2 := strconv.ParseFloat(h, 64)
+	if err1 != nil || err2 != nil || fw <= 0 || fh <= 0 {
+		return 0, bad
+	}
+	return fw / fh, nil
+}
+
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CacheDir returns the directory vista keeps session-independent caches in,
+// or "" if the user cache dir can't be determined.
+func CacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "vista")
+}
+
+// DataDir returns the directory for vista's persistent user data such as
+// favorites: $XDG_DATA_HOME/vista, or ~/.local/share/vista.
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "vista")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "share", "vista")
+}
+
+// SocketPath returns the control socket `vista daemon` listens on for
+// `vista ctl`: $XDG_RUNTIME_DIR/vista.sock, or daemon.sock in CacheDir.
+func SocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "vista.sock")
+	}
+	return filepath.Join(CacheDir(), "daemon.sock")
+}
+
+// ResolvedDownloadDir returns DownloadDir with ~, ~user and environment
+// variables expanded.
+func (c *Config) ResolvedDownloadDir() string {
+	return ExpandPath(c.DownloadDir)
+}
+
+// ResolvedScript returns Script with its command path expanded like
+// ResolvedDownloadDir. Arguments and quoted command paths are passed
+// through untouched.
+func (c *Config) ResolvedScript() string {
+	script := strings.TrimLeft(c.Script, " \t")
+	if script == "" || script[0] == '"' || script[0] == '\'' {
+		return script
+	}
+	cmd, args, found := strings.Cut(script, " ")
+	if !found {
+		return ExpandPath(cmd)
+	}
+	return ExpandPath(cmd) + " " + args
 }
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package config
+
+// ownedByRoot is always false where file ownership isn't a uid; the system
+// lock file is then ignored.
+func ownedByRoot(string) bool {
+	return false
+}
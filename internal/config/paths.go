@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandPath expands a leading ~ or ~user and any $VAR / ${VAR} references
+// in p. XDG user directories such as $XDG_PICTURES_DIR fall back to
+// ~/.config/user-dirs.dirs when they aren't set in the environment.
+func ExpandPath(p string) string {
+	p = os.Expand(p, lookupPathVar)
+
+	if !strings.HasPrefix(p, "~") {
+		return p
+	}
+	name, rest, _ := strings.Cut(p[1:], "/")
+	var home string
+	if name == "" {
+		h, err := os.UserHomeDir()
+		if err != nil {
+			return p
+		}
+		home = h
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return p
+		}
+		home = u.HomeDir
+	}
+	return filepath.Join(home, rest)
+}
+
+func lookupPathVar(name string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	if name == "HOME" {
+		home, _ := os.UserHomeDir()
+		return home
+	}
+	if strings.HasPrefix(name, "XDG_") && strings.HasSuffix(name, "_DIR") {
+		return xdgUserDir(name)
+	}
+	return ""
+}
+
+// xdgUserDir reads a directory such as XDG_PICTURES_DIR from the
+// user-dirs.dirs file written by xdg-user-dirs-update.
+func xdgUserDir(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	f, err := os.Open(filepath.Join(configHome, "user-dirs.dirs"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || key != name {
+			continue
+		}
+		val = strings.Trim(val, `"`)
+		// The file only ever references $HOME.
+		return strings.ReplaceAll(val, "$HOME", home)
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package dirsync
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/store"
+)
+
+// Target is a remote directory given as [user@]host:path, as for scp. An
+// empty Path leaves the choice to the other side: its download_dir.
+type Target struct {
+	Host string
+	Path string
+}
+
+// ParseTarget parses [user@]host[:path].
+func ParseTarget(s string) (Target, error) {
+	host, path, _ := strings.Cut(s, ":")
+	if host == "" || strings.HasPrefix(host, "-") {
+		return Target{}, fmt.Errorf("invalid remote %q: use [user@]host[:path]", s)
+	}
+	return Target{Host: host, Path: path}, nil
+}
+
+func (t Target) String() string {
+	if t.Path == "" {
+		return t.Host
+	}
+	return t.Host + ":" + t.Path
+}
+
+// Client talks to a Serve on the other end of a stream.
+type Client struct {
+	enc  *gob.Encoder
+	dec  *gob.Decoder
+	w    io.Closer
+	wait func() error
+}
+
+// NewClient returns a Client speaking over r and w. Close closes w.
+func NewClient(r io.Reader, w io.WriteCloser) *Client {
+	return &Client{enc: gob.NewEncoder(w), dec: gob.NewDecoder(r), w: w}
+}
+
+// Dial starts `remoteVista sync-serve [path]` on t's host over ssh and
+// returns a Client for it. The session's errors go to stderr, and ssh
+// asks for any password on the terminal as usual.
+func Dial(ctx context.Context, t Target, remoteVista string) (*Client, error) {
+	command := shellQuote(remoteVista) + " sync-serve"
+	if t.Path != "" {
+		command += " " + shellQuote(t.Path)
+	}
+	cmd := exec.CommandContext(ctx, "ssh", "-e", "none", "--", t.Host, command)
+	cmd.Stderr = os.Stderr
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting ssh: %w", err)
+	}
+	c := NewClient(r, w)
+	c.wait = cmd.Wait
+	return c, nil
+}
+
+// shellQuote quotes s for the remote POSIX shell, leaving a leading ~/
+// outside the quotes so it still expands.
+func shellQuote(s string) string {
+	prefix := ""
+	if rest, ok := strings.CutPrefix(s, "~/"); ok {
+		prefix, s = "~/", rest
+	}
+	return prefix + "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (c *Client) call(req request) (response, error) {
+	if err := c.enc.Encode(req); err != nil {
+		return response{}, err
+	}
+	return c.reply()
+}
+
+// reply reads the answer to the last request.
+func (c *Client) reply() (response, error) {
+	var resp response
+	if err := c.dec.Decode(&resp); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = errors.New("the remote side closed the connection")
+		}
+		return resp, err
+	}
+	if resp.Err != "" {
+		return resp, errors.New(resp.Err)
+	}
+	return resp, nil
+}
+
+// List returns the remote directory's manifest.
+func (c *Client) List() ([]File, error) {
+	resp, err := c.call(request{Op: "list"})
+	return resp.Files, err
+}
+
+// Put sends f from dir, streaming it in chunks.
+func (c *Client) Put(dir string, f File) error {
+	p, err := localPath(dir, f.Name)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	// It may have changed since the manifest.
+	f.Size, f.ModTime = info.Size(), info.ModTime()
+	if err := c.enc.Encode(request{Op: "put", File: f}); err != nil {
+		return err
+	}
+	serr := sendChunks(c.enc, file)
+	_, err = c.reply()
+	if serr != nil {
+		return serr
+	}
+	return err
+}
+
+// Get fetches f into dir and returns its path.
+func (c *Client) Get(dir string, f File) (string, error) {
+	resp, err := c.call(request{Op: "get", File: f})
+	if err != nil {
+		return "", err
+	}
+	resp.File.Name = f.Name
+	return writeFile(dir, resp.File, c.dec)
+}
+
+// Index returns the remote side's stores as they apply to its directory.
+func (c *Client) Index() (store.Portable, error) {
+	resp, err := c.call(request{Op: "index"})
+	return resp.Index, err
+}
+
+// Merge merges p into the remote side's stores.
+func (c *Client) Merge(p store.Portable) error {
+	_, err := c.call(request{Op: "merge", Index: p})
+	return err
+}
+
+// Close ends the session, waiting for the other side to finish.
+func (c *Client) Close() error {
+	_, err := c.call(request{Op: "done"})
+	if cerr := c.w.Close(); err == nil {
+		err = cerr
+	}
+	if c.wait != nil {
+		if werr := c.wait(); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,285 @@
+// Package dirsync copies the wallpapers of one directory to another over a
+// byte stream, such as an ssh session running `vista sync-serve` on the
+// other machine. Like rsync's default quick check, a file is sent only when
+// the other side lacks it or holds one of a different size or modification
+// time; nothing is ever deleted. File data is streamed in chunks. The
+// receiving side also merges in the sender's ratings, favorites and library
+// metadata; see store.Portable.
+package dirsync
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+// File describes one wallpaper of a synced directory.
+type File struct {
+	Name    string // slash-separated path relative to the directory
+	Size    int64
+	ModTime time.Time
+}
+
+// Manifest lists the images under dir, sorted by name. Hidden files, such
+// as downloads in progress, are skipped. A missing dir is empty.
+func Manifest(dir string) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == dir {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.Name()[0] == '.' && p != dir {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() || !wallpaper.IsImage(p) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Name: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	slices.SortFunc(files, func(a, b File) int { return strings.Compare(a.Name, b.Name) })
+	return files, err
+}
+
+// Diff returns the files of src that dst lacks or holds a different
+// version of.
+func Diff(src, dst []File) []File {
+	have := make(map[string]File, len(dst))
+	for _, f := range dst {
+		have[f.Name] = f
+	}
+	var out []File
+	for _, f := range src {
+		if d, ok := have[f.Name]; !ok || d.Size != f.Size || !d.ModTime.Equal(f.ModTime) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// chunkSize is the most file data one message carries, so neither side
+// holds more than this of a file in memory.
+const chunkSize = 1 << 20
+
+// request is what a Client sends; Op is list, put, get, index, merge or
+// done. A put is followed by the file's chunks.
+type request struct {
+	Op    string
+	File  File
+	Index store.Portable // merge
+}
+
+// response answers a request. Err is set when it failed; otherwise a get
+// is followed by the file's chunks.
+type response struct {
+	Files []File         // list
+	File  File           // get: the file as it is now
+	Index store.Portable // index
+	Err   string
+}
+
+// chunk is up to chunkSize bytes of a file. An empty chunk ends the file,
+// and one with Err set abandons it.
+type chunk struct {
+	Data []byte
+	Err  string
+}
+
+// Serve answers the requests of a Client read from r, writing the replies
+// to w, for dir and its stores until the client is done or r ends. It
+// returns the paths of the files it received.
+func Serve(r io.Reader, w io.Writer, dir string, stores store.Stores) ([]string, error) {
+	dec, enc := gob.NewDecoder(r), gob.NewEncoder(w)
+	var received []string
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return received, nil
+			}
+			return received, err
+		}
+		var resp response
+		var err error
+		switch req.Op {
+		case "list":
+			resp.Files, err = Manifest(dir)
+		case "put":
+			var p string
+			if p, err = writeFile(dir, req.File, dec); err == nil {
+				received = append(received, p)
+			}
+		case "get":
+			if err := serveFile(enc, dir, req.File.Name); err != nil {
+				return received, err
+			}
+			continue
+		case "index":
+			resp.Index, err = stores.Export(dir)
+		case "merge":
+			err = stores.Merge(dir, req.Index)
+		case "done":
+			return received, enc.Encode(resp)
+		default:
+			err = fmt.Errorf("unknown request %q", req.Op)
+		}
+		if err != nil {
+			resp.Err = err.Error()
+		}
+		if err := enc.Encode(resp); err != nil {
+			return received, err
+		}
+	}
+}
+
+// serveFile answers a get of the file called name under dir: a response
+// describing it, then its chunks. Only a failure to write to enc is
+// returned; the others go to the client.
+func serveFile(enc *gob.Encoder, dir, name string) error {
+	p, err := localPath(dir, name)
+	var f *os.File
+	if err == nil {
+		f, err = os.Open(p)
+	}
+	var info fs.FileInfo
+	if err == nil {
+		defer f.Close()
+		info, err = f.Stat()
+	}
+	if err != nil {
+		return enc.Encode(response{Err: err.Error()})
+	}
+	if err := enc.Encode(response{File: File{Name: name, Size: info.Size(), ModTime: info.ModTime()}}); err != nil {
+		return err
+	}
+	return sendChunks(enc, f)
+}
+
+// sendChunks writes the rest of r to enc as chunks, ending with an empty
+// one, or one carrying the error if reading fails.
+func sendChunks(enc *gob.Encoder, r io.Reader) error {
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := enc.Encode(chunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return enc.Encode(chunk{})
+		case err != nil:
+			if eerr := enc.Encode(chunk{Err: err.Error()}); eerr != nil {
+				return eerr
+			}
+			return err
+		}
+	}
+}
+
+// receiveChunks copies the chunks read from dec to w until the file ends,
+// returning how many bytes it got. When w fails the rest is still read,
+// so the stream stays in step.
+func receiveChunks(dec *gob.Decoder, w io.Writer) (int64, error) {
+	var n int64
+	var werr error
+	for {
+		var c chunk
+		if err := dec.Decode(&c); err != nil {
+			return n, err
+		}
+		switch {
+		case c.Err != "":
+			return n, errors.New(c.Err)
+		case len(c.Data) == 0:
+			return n, werr
+		case werr == nil:
+			var m int
+			m, werr = w.Write(c.Data)
+			n += int64(m)
+		}
+	}
+}
+
+// localPath is where the file called name lives under dir. Names that
+// would escape dir are refused.
+func localPath(dir, name string) (string, error) {
+	rel := filepath.FromSlash(name)
+	if !filepath.IsLocal(rel) || !wallpaper.IsImage(rel) {
+		return "", fmt.Errorf("refusing file name %q", name)
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+// writeFile saves f, read as chunks from dec, under dir, replacing any
+// older version only once it is complete, and returns its path. The chunks
+// are read even when the file can't be written.
+func writeFile(dir string, f File, dec *gob.Decoder) (string, error) {
+	p, err := localPath(dir, f.Name)
+	var tmp *os.File
+	if err == nil {
+		tmp, err = createTemp(p)
+	}
+	if err != nil {
+		receiveChunks(dec, io.Discard) //nolint:errcheck
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	n, err := receiveChunks(dec, tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n != f.Size {
+		err = fmt.Errorf("%s: got %d bytes, expected %d", f.Name, n, f.Size)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.Chtimes(tmp.Name(), f.ModTime, f.ModTime); err != nil {
+		return "", err
+	}
+	return p, os.Rename(tmp.Name(), p)
+}
+
+// createTemp creates a temp file beside p, and any missing directories,
+// with the permissions p should end up with.
+func createTemp(p string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".sync-*")
+	if err != nil {
+		return nil, err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return tmp, nil
+}
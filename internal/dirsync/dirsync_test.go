@@ -0,0 +1,155 @@
+package dirsync
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// openStores opens empty stores under a temp dir.
+func openStores(t *testing.T) store.Stores {
+	t.Helper()
+	dir := t.TempDir()
+	lib, err := store.OpenLibrary(filepath.Join(dir, "library.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratings, err := store.OpenRatings(filepath.Join(dir, "ratings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	favorites, err := store.OpenFavorites(filepath.Join(dir, "favorites.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store.Stores{Library: lib, Ratings: ratings, Favorites: favorites}
+}
+
+// writeImage writes size random bytes to name under dir, dated an hour ago.
+func writeImage(t *testing.T, dir, name string, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(rand.IntN(256))
+	}
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(p, old, old); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// checkCopy checks that name under dst matches src in content and time.
+func checkCopy(t *testing.T, src, dst, name string) {
+	t.Helper()
+	want, err := os.ReadFile(filepath.Join(src, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dst, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: copied %d bytes differ from the %d sent", name, len(got), len(want))
+	}
+	si, _ := os.Stat(filepath.Join(src, name))
+	di, _ := os.Stat(filepath.Join(dst, name))
+	if !si.ModTime().Equal(di.ModTime()) {
+		t.Errorf("%s: modification time %v, want %v", name, di.ModTime(), si.ModTime())
+	}
+}
+
+func TestSync(t *testing.T) {
+	local, remote := t.TempDir(), t.TempDir()
+	localStores, remoteStores := openStores(t), openStores(t)
+
+	// Larger than a chunk, so both directions stream several.
+	writeImage(t, local, "up.jpg", 2*chunkSize+123)
+	writeImage(t, remote, "down.jpg", chunkSize+1)
+	if err := localStores.Ratings.Rate("up.jpg", 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := localStores.Favorites.Toggle(provider.Wallpaper{Path: filepath.Join(local, "up.jpg")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := remoteStores.Ratings.Rate("down.jpg", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	toServer, fromClient := io.Pipe()
+	toClient, fromServer := io.Pipe()
+	done := make(chan []string)
+	go func() {
+		received, err := Serve(toServer, fromServer, remote, remoteStores)
+		if err != nil {
+			t.Error(err)
+		}
+		fromServer.Close()
+		done <- received
+	}()
+	c := NewClient(toClient, fromClient)
+
+	files, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "down.jpg" {
+		t.Fatalf("List = %v, want down.jpg", files)
+	}
+	if err := c.Put(local, File{Name: "up.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(local, files[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put(local, File{Name: "../escape.jpg"}); err == nil {
+		t.Error("Put of a name outside the directory succeeded")
+	}
+	p, err := localStores.Export(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Merge(p); err != nil {
+		t.Fatal(err)
+	}
+	index, err := c.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if received := <-done; len(received) != 1 || filepath.Base(received[0]) != "up.jpg" {
+		t.Errorf("server received %v, want up.jpg", received)
+	}
+	if err := localStores.Merge(local, index); err != nil {
+		t.Fatal(err)
+	}
+
+	checkCopy(t, local, remote, "up.jpg")
+	checkCopy(t, remote, local, "down.jpg")
+	if got := remoteStores.Ratings.Get("up.jpg").Stars; got != 4 {
+		t.Errorf("remote rating of up.jpg = %d, want 4", got)
+	}
+	if !remoteStores.Favorites.Has(provider.Wallpaper{Path: filepath.Join(remote, "up.jpg")}) {
+		t.Errorf("remote favorites = %v, want up.jpg under %s", remoteStores.Favorites.List(), remote)
+	}
+	if got := localStores.Ratings.Get("down.jpg").Stars; got != 2 {
+		t.Errorf("local rating of down.jpg = %d, want 2", got)
+	}
+	if n := len(localStores.Library.Entries()); n != 2 {
+		t.Errorf("local library has %d entries, want 2", n)
+	}
+}
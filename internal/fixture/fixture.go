@@ -0,0 +1,114 @@
+// Package fixture serves search results and images from files on disk,
+// so the grid can be driven without network access or an API key when
+// reproducing bugs.
+//
+// A fixture directory holds one page-N.json per page, numbered from 1, in
+// the shape of a Wallhaven search response: {"data": [...], "meta": {...}}.
+// The output of `vista --json` is one such page. Image paths and thumbnails
+// that are relative are resolved against the directory; URLs are left for
+// the downloader as usual.
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// Source is a provider.Provider and provider.Detailer backed by a fixture
+// directory.
+type Source struct {
+	Dir string
+}
+
+// Open returns the fixture in dir, checking that it has a first page.
+func Open(dir string) (*Source, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(abs, "page-1.json")); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", dir, err)
+	}
+	return &Source{Dir: abs}, nil
+}
+
+// Page reads page-<page>.json. Meta.CurrentPage and, when missing,
+// Meta.LastPage are filled in from the files present.
+func (s *Source) Page(page int) ([]provider.Wallpaper, provider.Meta, error) {
+	var resp struct {
+		Data []provider.Wallpaper `json:"data"`
+		Meta provider.Meta        `json:"meta"`
+	}
+	b, err := os.ReadFile(filepath.Join(s.Dir, fmt.Sprintf("page-%d.json", page)))
+	if err != nil {
+		return nil, provider.Meta{}, err
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, provider.Meta{}, fmt.Errorf("page-%d.json: %w", page, err)
+	}
+	for i := range resp.Data {
+		wp := &resp.Data[i]
+		wp.Path = s.resolve(wp.Path)
+		wp.Thumbs.Small = s.resolve(wp.Thumbs.Small)
+		wp.Thumbs.Large = s.resolve(wp.Thumbs.Large)
+		wp.Thumbs.Original = s.resolve(wp.Thumbs.Original)
+	}
+	resp.Meta.CurrentPage = page
+	if resp.Meta.LastPage == 0 {
+		resp.Meta.LastPage = s.lastPage()
+	}
+	return resp.Data, resp.Meta, nil
+}
+
+// PageContext is Page; reading a file needs no cancelling.
+func (s *Source) PageContext(_ context.Context, page int) ([]provider.Wallpaper, provider.Meta, error) {
+	return s.Page(page)
+}
+
+// GetWallpaper returns the details of the wallpaper with id on any page:
+// the fixture has no more than the search results carry.
+func (s *Source) GetWallpaper(id string) (provider.Details, error) {
+	for page := 1; page <= s.lastPage(); page++ {
+		wallpapers, _, err := s.Page(page)
+		if err != nil {
+			return provider.Details{}, err
+		}
+		for _, wp := range wallpapers {
+			if wp.ID == id {
+				return provider.Details{Wallpaper: wp, Favorites: wp.Favorites, Colors: wp.Colors, Source: wp.Source}, nil
+			}
+		}
+	}
+	return provider.Details{}, fmt.Errorf("fixture: no wallpaper %s", id)
+}
+
+// resolve makes a relative path absolute within the fixture; URLs,
+// absolute paths and empty values are returned unchanged.
+func (s *Source) resolve(p string) string {
+	if p == "" || filepath.IsAbs(p) || strings.Contains(p, "://") {
+		return p
+	}
+	return filepath.Join(s.Dir, p)
+}
+
+// lastPage is the highest N with page-1.json to page-N.json all present.
+func (s *Source) lastPage() int {
+	n := 0
+	for {
+		if _, err := os.Stat(filepath.Join(s.Dir, fmt.Sprintf("page-%d.json", n+1))); err != nil {
+			return n
+		}
+		n++
+	}
+}
+
+var (
+	_ provider.ContextProvider = (*Source)(nil)
+	_ provider.Detailer        = (*Source)(nil)
+)
@@ -0,0 +1,315 @@
+package i18n
+
+var de = map[string]string{
+	// usage
+	"Usage:":    "Aufruf:",
+	"Commands:": "Befehle:",
+	"Flags:":    "Optionen:",
+	"Flags override values from ~/.config/vista/config.yaml.":                                                 "Optionen überschreiben Werte aus ~/.config/vista/config.yaml.",
+	"swww transition type, e.g. fade, wipe, grow":                                                             "swww-Übergangstyp, z. B. fade, wipe, grow",
+	"transition length in seconds":                                                                            "Übergangsdauer in Sekunden",
+	"transition origin, e.g. center or 0.8,0.9":                                                               "Ausgangspunkt des Übergangs, z. B. center oder 0.8,0.9",
+	"set a solid colour; two comma-separated colours make a gradient":                                         "eine Volltonfarbe setzen; zwei kommagetrennte Farben ergeben einen Verlauf",
+	"browse wallpapers starred with f":                                                                        "mit f markierte Hintergründe durchsuchen",
+	"wallpapers with every tag (-tag excludes, a number is a tag ID)":                                         "Hintergründe mit allen Tags (-tag schließt aus, eine Zahl ist eine Tag-ID)",
+	"Queries accept +tag (required), -tag (excluded) and id:N (exact tag ID).":                                "Suchanfragen akzeptieren +tag (erforderlich), -tag (ausgeschlossen) und id:N (exakte Tag-ID).",
+	"cycle through a playlist (a in the grid queues to \"queue\")":                                            "eine Playlist abspielen (a im Raster fügt zu \"queue\" hinzu)",
+	"index an existing folder (--lookup confirms Wallhaven IDs)":                                              "einen vorhandenen Ordner indizieren (--lookup bestätigt Wallhaven-IDs)",
+	"list imported wallpapers (--sort rating, --min-rating n)":                                                "importierte Hintergründe auflisten (--sort rating, --min-rating n)",
+	"find a local image's Wallhaven page by file name or image match":                                         "die Wallhaven-Seite eines lokalen Bildes über Dateinamen oder Bildvergleich finden",
+	"print the photographer credit for the current wallpaper":                                                 "Fotografen-Nennung für das aktuelle Hintergrundbild ausgeben",
+	"set a wallpaper every --interval (30m), matching --query, picked by --strategy":                          "alle --interval (30m) einen Hintergrund passend zu --query setzen, ausgewählt nach --strategy",
+	"set a result without opening the grid, then exit (or least-recent, top-unseen, top-rated, palette)":      "ein Ergebnis ohne Raster setzen und beenden (oder least-recent, top-unseen, top-rated, palette)",
+	"with --apply or daemon, print search_started, downloaded, set and error events as JSON lines":            "mit --apply oder daemon Ereignisse search_started, downloaded, set und error als JSON-Zeilen ausgeben",
+	"browse downloaded and imported wallpapers offline":                                                       "heruntergeladene und importierte Hintergründe offline durchsuchen",
+	"make the running daemon change wallpaper now":                                                            "den laufenden Daemon sofort wechseln lassen",
+	"print every change with its time, ID and page (--since date)":                                            "jeden Wechsel mit Zeit, ID und Seite ausgeben (--since Datum)",
+	"print the first page of results with its meta as JSON and exit":                                          "die erste Ergebnisseite mit Metadaten als JSON ausgeben und beenden",
+	"restore the previous wallpaper; repeat to step further back":                                             "vorheriges Hintergrundbild wiederherstellen; wiederholen, um weiter zurückzugehen",
+	"browse only: never set or delete wallpapers or run scripts":                                              "nur ansehen: nie Hintergrundbilder setzen, löschen oder Skripte ausführen",
+	"play back a grid session saved with --record":                                                            "eine mit --record gespeicherte Grid-Sitzung abspielen",
+	"save the grid session as an asciinema cast, for bug reports":                                             "die Grid-Sitzung als asciinema-Cast speichern, für Fehlerberichte",
+	"full-screen photo frame of --query, every --interval (5m), within --hours":                               "Vollbild-Bilderrahmen für --query, alle --interval (5m), innerhalb --hours",
+	"dominant colour from Wallhaven's palette, e.g. 0066cc or #cc0000":                                        "dominante Farbe aus der Wallhaven-Palette, z. B. 0066cc oder #cc0000",
+	"only these exact resolutions e.g. 2560x1440,3840x2160":                                                   "nur diese exakten Auflösungen, z. B. 2560x1440,3840x2160",
+	"download results and print paths, an m3u or a feh/swww/swaybg script (--format)":                         "Ergebnisse herunterladen und als Pfade, m3u oder feh/swww/swaybg-Skript ausgeben (--format)",
+	"clean up downloads, largest (or least recently set) first: space keeps, d deletes":                       "Downloads aufräumen, größte (oder am längsten nicht gesetzte) zuerst: Leertaste behält, d löscht",
+	"toplist window for top: 1d, 3d, 1w, 1M, 3M, 6M or 1y":                                                    "Toplisten-Zeitraum für top: 1d, 3d, 1w, 1M, 3M, 6M oder 1y",
+	"pick from starter searches: minimal, nature, space, anime, dark, city, abstract":                         "aus Einstiegssuchen wählen: minimal, nature, space, anime, dark, city, abstract",
+	"wallpapers uploaded by a Wallhaven user, newest first":                                                   "von einem Wallhaven-Nutzer hochgeladene Hintergründe, neueste zuerst",
+	"serve results from page-N.json files (e.g. saved --json output) instead of Wallhaven":                    "Ergebnisse aus page-N.json-Dateien (z. B. gespeicherter --json-Ausgabe) statt von Wallhaven liefern",
+	"open one Wallhaven wallpaper by ID or link; enter sets it":                                               "ein Wallhaven-Hintergrundbild per ID oder Link öffnen; Enter setzt es",
+	"set the wallpaper of the day for --query; the same one all day":                                          "das Hintergrundbild des Tages für --query setzen; den ganzen Tag dasselbe",
+	"never run external programs: built-in renderers and wallpaper setting only, no scripts, swww or browser": "nie externe Programme starten: nur eingebaute Renderer und Hintergrund-Setzen, keine Skripte, kein swww oder Browser",
+	"image posts from a subreddit, --sort hot, new, top or rising, --time for top":                            "Bildbeiträge aus einem Subreddit, --sort hot, new, top oder rising, --time für top",
+	"rate a wallpaper or file 0-5 (0 clears), then an optional note":                                          "einen Hintergrund oder eine Datei mit 0-5 bewerten (0 löscht), danach eine optionale Notiz",
+	"write a shareable HTML gallery of the results to --out (gallery.html)":                                   "eine teilbare HTML-Galerie der Ergebnisse nach --out (gallery.html) schreiben",
+	"copy new downloads to another machine over ssh (default sync_remote)":                                    "neue Downloads per ssh auf einen anderen Rechner kopieren (Standard: sync_remote)",
+	"copy new wallpapers from another machine into download_dir":                                              "neue Hintergründe von einem anderen Rechner nach download_dir kopieren",
+	"NASA's picture of the day, or --date, --from/--to a range, --random n":                                   "NASAs Bild des Tages, oder --date, --from/--to einen Zeitraum, --random n",
+
+	"search by keyword":    "nach Stichwort suchen",
+	"top-rated wallpapers": "bestbewertete Hintergründe",
+	"trending wallpapers":  "angesagte Hintergründe",
+	"newest wallpapers":    "neueste Hintergründe",
+	"random wallpapers":    "zufällige Hintergründe",
+	"browse wallpapers you have set, most recent first": "gesetzte Hintergründe durchsuchen, neueste zuerst",
+	"list your Wallhaven collections (needs --apikey)":  "deine Wallhaven-Sammlungen auflisten (benötigt --apikey)",
+
+	"Wallhaven API key":                                                    "Wallhaven-API-Schlüssel",
+	"comma-separated: sfw,sketchy,nsfw":                                    "kommagetrennt: sfw,sketchy,nsfw",
+	"comma-separated: general,anime,people":                                "kommagetrennt: general,anime,people",
+	"minimum resolution e.g. 1920x1080":                                    "Mindestauflösung, z. B. 1920x1080",
+	"comma-separated aspect ratios e.g. 16x9,16x10, or landscape/portrait": "kommagetrennte Seitenverhältnisse, z. B. 16x9,16x10, oder landscape/portrait",
+	"directory to save wallpapers":                                         "Verzeichnis für heruntergeladene Hintergründe",
+	"script to run instead of setting the wallpaper; {path}, {id}, {url}, {monitor} are substituted": "Skript statt des direkten Setzens; {path}, {id}, {url}, {monitor} werden ersetzt",
+	"ask before quitting while downloads are in flight":                                              "vor dem Beenden bei laufenden Downloads nachfragen",
+	"exit after N minutes without input (0 disables)":                                                "nach N Minuten ohne Eingabe beenden (0 deaktiviert)",
+	"Enter sets the wallpaper without leaving the grid":                                              "Enter setzt den Hintergrund, ohne das Raster zu verlassen",
+	"seconds per wallpaper in the 'p' slideshow preview":                                             "Sekunden pro Bild in der Diashow-Vorschau ('p')",
+	"grid thumbnail source: small, large or original":                                                "Vorschaubildquelle: small, large oder original",
+	"image renderer: auto, chafa, kitty, sixel, iterm, ueberzug":                                     "Bilddarstellung: auto, chafa, kitty, sixel, iterm, ueberzug",
+	"plain ASCII UI (no box drawing or block characters)":                                            "reine ASCII-Oberfläche (keine Rahmen- oder Blockzeichen)",
+	"append diagnostics (render failures etc.) to this file":                                         "Diagnosen (Darstellungsfehler usw.) an diese Datei anhängen",
+	"print progress messages":                                                                        "Fortschrittsmeldungen ausgeben",
+
+	// main
+	"Unknown command: %q":                                            "Unbekannter Befehl: %q",
+	"Error: %v":                                                      "Fehler: %v",
+	"Error reading history: %v":                                      "Fehler beim Lesen des Verlaufs: %v",
+	"Warning: could not load config: %v":                             "Warnung: Konfiguration konnte nicht geladen werden: %v",
+	"No downloaded wallpapers found.":                                "Keine heruntergeladenen Hintergründe gefunden.",
+	"Found %d downloaded wallpapers. Loading...":                     "%d heruntergeladene Hintergründe gefunden. Lade...",
+	"Searching for %q":                                               "Suche nach %q",
+	"Fetching top wallpapers":                                        "Lade bestbewertete Hintergründe",
+	"Fetching hot wallpapers":                                        "Lade angesagte Hintergründe",
+	"Fetching new wallpapers":                                        "Lade neue Hintergründe",
+	"Fetching random wallpapers":                                     "Lade zufällige Hintergründe",
+	"No results found.":                                              "Keine Ergebnisse gefunden.",
+	"Found %d wallpapers across %d pages. Loading...":                "%d Hintergründe auf %d Seiten gefunden. Lade...",
+	"Warning: chafa not found, falling back to placeholder renderer": "Warnung: chafa nicht gefunden, verwende Platzhalter",
+	"Invalid border style %q: use %s":                                "Ungültiger Rahmenstil %q: %s verwenden",
+	"Invalid thumb size %q: use small, large or original":            "Ungültige Vorschaugröße %q: small, large oder original verwenden",
+	"Error opening log file: %v":                                     "Fehler beim Öffnen der Logdatei: %v",
+	"%-8d %-30s %d wallpapers":                                       "%-8d %-30s %d Hintergründe",
+	"set needs --color '#rrggbb' or --color '#top,#bottom'":          "set braucht --color '#rrggbb' oder --color '#oben,#unten'",
+	"set takes at most two colours":                                  "set akzeptiert höchstens zwei Farben",
+	"No favorites yet - press f in the grid to add one.":             "Noch keine Favoriten - drücke f im Raster, um einen hinzuzufügen.",
+	"Fetching wallpapers tagged %s":                                  "Lade Hintergründe mit Tag %s",
+	"Skipping %s: %v":                                                "Überspringe %s: %v",
+	"Playing %s for %s":                                              "Zeige %s für %s",
+	"library needs a subcommand: import, list or rate":               "library braucht einen Unterbefehl: import, list oder rate",
+	"library import needs a directory":                               "library import braucht ein Verzeichnis",
+	"Added %d, unchanged %d, %d duplicates":                          "%d hinzugefügt, %d unverändert, %d Duplikate",
+	"duplicate:":                                                     "Duplikat:",
+	"unknown library subcommand %q":                                  "unbekannter library-Unterbefehl %q",
+	"Confirmed %d Wallhaven IDs":                                     "%d Wallhaven-IDs bestätigt",
+	"lookup needs exactly one file":                                  "lookup braucht genau eine Datei",
+	"File name ID %s not found, comparing images instead":            "ID %s aus dem Dateinamen nicht gefunden, vergleiche stattdessen Bilder",
+	"no matching wallpaper found on Wallhaven":                       "kein passender Hintergrund auf Wallhaven gefunden",
+	"image match, %d/64 bits differ":                                 "Bildvergleich, %d/64 Bits unterschiedlich",
+	"Searching %s wallpapers with colour #%s...":                     "Suche %s-Hintergründe mit Farbe #%s...",
+	"no current wallpaper recorded":                                  "kein aktuelles Hintergrundbild gespeichert",
+	"No attribution recorded for this wallpaper":                     "Für dieses Hintergrundbild ist keine Quellenangabe gespeichert",
+	"Author":                                                              "Autor",
+	"Profile":                                                             "Profil",
+	"--interval must be positive":                                         "--interval muss positiv sein",
+	"Set %s, next change in %s":                                           "%s gesetzt, nächster Wechsel in %s",
+	"Daemon stopped":                                                      "Daemon beendet",
+	"no wallpapers match the query":                                       "keine Hintergrundbilder passen zur Suche",
+	"invalid --apply %q: use first or %s":                                 "ungültiges --apply %q: first oder %s verwenden",
+	"Wallpaper set: %s":                                                   "Hintergrundbild gesetzt: %s",
+	"invalid --events %q: use json":                                       "ungültiges --events %q: json verwenden",
+	"unknown daemon command %q":                                           "unbekannter Daemon-Befehl %q",
+	"a daemon is already running (%s)":                                    "ein Daemon läuft bereits (%s)",
+	"usage: vista ctl next":                                               "Verwendung: vista ctl next",
+	"no daemon is running; start one with vista daemon":                   "kein Daemon läuft; mit vista daemon starten",
+	"No wallpaper has been set yet.":                                      "Es wurde noch kein Hintergrundbild gesetzt.",
+	"no wallpaper has been set yet":                                       "es wurde noch kein Hintergrundbild gesetzt",
+	"Found %d wallpapers in your history. Loading...":                     "%d Hintergrundbilder im Verlauf gefunden. Lade...",
+	"invalid --since %q: use YYYY-MM-DD":                                  "ungültiges --since %q: JJJJ-MM-TT verwenden",
+	"nothing to undo":                                                     "nichts rückgängig zu machen",
+	"Restored %s":                                                         "%s wiederhergestellt",
+	"%s sets wallpapers, which --browse-only disables":                    "%s setzt Hintergrundbilder, was --browse-only abschaltet",
+	"purity is locked to sfw":                                             "Reinheit ist auf sfw festgelegt",
+	"usage: vista replay <file.cast>":                                     "Verwendung: vista replay <datei.cast>",
+	"invalid --hours %q: use from-to, e.g. 7-23":                          "ungültiges --hours %q: von-bis verwenden, z. B. 7-23",
+	"unknown --format %q: use %s":                                         "unbekanntes --format %q: %s verwenden",
+	"-n must be at least 1":                                               "-n muss mindestens 1 sein",
+	"Downloading %d wallpapers to %s...":                                  "Lade %d Hintergründe nach %s herunter...",
+	"no wallpapers could be downloaded":                                   "keine Hintergründe konnten heruntergeladen werden",
+	"%d downloads failed":                                                 "%d Downloads fehlgeschlagen",
+	"unknown --sort %q: use size or set":                                  "unbekanntes --sort %q: size oder set verwenden",
+	"Kept %d, deleted %d, reclaimed %.1f MB":                              "%d behalten, %d gelöscht, %.1f MB freigegeben",
+	"unknown --strategy %q: use %s":                                       "unbekannte --strategy %q: %s verwenden",
+	"Invalid selection style %q: use %s":                                  "Ungültiger Auswahlstil %q: %s verwenden",
+	"Warning: search failed (%v), using the local library":                "Warnung: Suche fehlgeschlagen (%v), verwende die lokale Bibliothek",
+	"Invalid fallback %q: use %s":                                         "Ungültiger Fallback %q: %s verwenden",
+	"Pick a starting point:":                                              "Wähle einen Einstieg:",
+	"Number or name [1-%d]: ":                                             "Nummer oder Name [1-%d]: ",
+	"unknown collection %q: use %s":                                       "unbekannte Sammlung %q: verwende %s",
+	"Fetching %s wallpapers":                                              "Lade %s-Hintergründe",
+	"clean, simple shapes and flat colour":                                "klare, einfache Formen und flache Farben",
+	"landscapes, forests, mountains and water":                            "Landschaften, Wälder, Berge und Wasser",
+	"planets, nebulae and starfields":                                     "Planeten, Nebel und Sternenfelder",
+	"anime and illustration":                                              "Anime und Illustration",
+	"dark, low-light wallpapers":                                          "dunkle Hintergründe mit wenig Licht",
+	"cityscapes and architecture":                                         "Stadtansichten und Architektur",
+	"abstract art and patterns":                                           "abstrakte Kunst und Muster",
+	"Fetching uploads by %s":                                              "Lade Uploads von %s",
+	"unknown --source %q: use fixture:<dir>":                              "unbekannte --source %q: verwende fixture:<Verzeichnis>",
+	"Warning: could not fetch account settings: %v":                       "Warnung: Kontoeinstellungen konnten nicht abgerufen werden: %v",
+	"not a Wallhaven ID or URL: %q":                                       "keine Wallhaven-ID oder -URL: %q",
+	"id needs exactly one wallpaper ID or URL":                            "id braucht genau eine Hintergrundbild-ID oder URL",
+	"Already set today: %s":                                               "Heute bereits gesetzt: %s",
+	"Wallpaper of the day: %s":                                            "Hintergrundbild des Tages: %s",
+	"Warning: --no-exec: not running script %q":                           "Warnung: --no-exec: Skript %q wird nicht ausgeführt",
+	"Warning: --no-exec: not running %s, using a built-in renderer":       "Warnung: --no-exec: %s wird nicht gestartet, eingebauter Renderer wird verwendet",
+	"reddit needs a subreddit, e.g. vista reddit wallpapers":              "reddit braucht ein Subreddit, z. B. vista reddit wallpapers",
+	"invalid min resolution %q: use WxH":                                  "ungültige Mindestauflösung %q: WxH verwenden",
+	"Fetching image posts from r/%s":                                      "Lade Bildbeiträge aus r/%s",
+	"unknown sort %q: use rating":                                         "unbekannte Sortierung %q: rating verwenden",
+	"library rate needs a wallpaper and a rating from 0 to 5":             "library rate braucht einen Hintergrund und eine Bewertung von 0 bis 5",
+	"invalid rating %q: use 0 to %d":                                      "ungültige Bewertung %q: 0 bis %d verwenden",
+	"%s is not in the library or download folder":                         "%s ist weder in der Bibliothek noch im Download-Ordner",
+	"Rated %s %d/%d":                                                      "%s mit %d/%d bewertet",
+	"every match is rated below %d":                                       "jeder Treffer ist schlechter als %d bewertet",
+	"Fetching %d thumbnails...":                                           "Lade %d Vorschaubilder...",
+	"no thumbnails could be downloaded":                                   "keine Vorschaubilder konnten heruntergeladen werden",
+	"%d thumbnails failed, leaving them out":                              "%d Vorschaubilder fehlgeschlagen, sie werden ausgelassen",
+	"Wrote %d wallpapers to %s":                                           "%d Hintergründe nach %s geschrieben",
+	"%s needs a remote: [user@]host[:path], or sync_remote in the config": "%s braucht ein Ziel: [user@]host[:path], oder sync_remote in der Konfiguration",
+	"--no-exec: %s needs ssh":                                             "--no-exec: %s braucht ssh",
+	"Copied %d wallpapers, %d already up to date":                         "%d Hintergründe kopiert, %d bereits aktuell",
+	"%d files could not be copied":                                        "%d Dateien konnten nicht kopiert werden",
+	"invalid --%s %q: use YYYY-MM-DD":                                     "ungültiges --%s %q: JJJJ-MM-TT verwenden",
+	"Fetching the Astronomy Picture of the Day":                           "Lade das Astronomiebild des Tages",
+	"No pictures found; videos are skipped.":                              "Keine Bilder gefunden; Videos werden übersprungen.",
+
+	// grid
+	"KEYS":                               "TASTEN",
+	"navigate":                           "bewegen",
+	"download + set":                     "herunterladen + setzen",
+	"set (stay open)":                    "setzen (offen bleiben)",
+	"open in browser":                    "im Browser öffnen",
+	"jump to random":                     "zu zufälligem Bild springen",
+	"slideshow preview":                  "Diashow-Vorschau",
+	"delete (local files)":               "löschen (lokale Dateien)",
+	"filter loaded results":              "geladene Ergebnisse filtern",
+	"clear filter":                       "Filter aufheben",
+	"toggle help":                        "Hilfe ein/aus",
+	"quit":                               "beenden",
+	"Failed to set %s: %v":               "%s konnte nicht gesetzt werden: %v",
+	"try on desktop, revert unless kept": "auf dem Desktop ausprobieren, ohne Bestätigung zurücksetzen",
+	"Kept %s":                            "%s behalten",
+	"No previous wallpaper to revert to": "Kein vorheriger Hintergrund zum Zurücksetzen",
+	"Failed to revert: %v":               "Zurücksetzen fehlgeschlagen: %v",
+	"Reverted to the previous wallpaper": "Vorheriger Hintergrund wiederhergestellt",
+	"Trying %s - enter keeps it, esc reverts (%ds)": "Teste %s - Enter behält, Esc setzt zurück (%ds)",
+	"toggle favorite":                  "Favorit an/aus",
+	"Saving favorites failed: %v":      "Speichern der Favoriten fehlgeschlagen: %v",
+	"Added %s to favorites":            "%s zu Favoriten hinzugefügt",
+	"Removed %s from favorites":        "%s aus Favoriten entfernt",
+	"add to the play queue":            "zur Warteschlange hinzufügen",
+	"Saving playlist failed: %v":       "Speichern der Playlist fehlgeschlagen: %v",
+	"Queued %s (%d in queue)":          "%s eingereiht (%d in der Warteschlange)",
+	"details":                          "Details",
+	"no metadata source for this list": "keine Metadatenquelle für diese Liste",
+	"Resolution":                       "Auflösung",
+	"Page":                             "Seite",
+	"File":                             "Datei",
+	"Size":                             "Größe",
+	"Type":                             "Typ",
+	"Category":                         "Kategorie",
+	"Purity":                           "Reinheit",
+	"Uploader":                         "Hochgeladen von",
+	"Uploaded":                         "Hochgeladen am",
+	"Views":                            "Aufrufe",
+	"Favorites":                        "Favoriten",
+	"Source":                           "Quelle",
+	"Colors":                           "Farben",
+	"Tags":                             "Tags",
+	"Details unavailable: %v":          "Details nicht verfügbar: %v",
+	"Loading details...":               "Lade Details...",
+	"i/esc back  o open in browser":    "i/Esc zurück  o im Browser öffnen",
+	"tag cloud of loaded results":      "Tag-Wolke der geladenen Ergebnisse",
+	"arrows move  enter search tag  c/esc back": "Pfeile bewegen  Enter Tag suchen  c/Esc zurück",
+	"Searching isn't available from this list":  "Suchen ist in dieser Liste nicht verfügbar",
+	"Searching for tag %s...":                   "Suche nach Tag %s...",
+	"Search failed: %v":                         "Suche fehlgeschlagen: %v",
+	"No wallpapers tagged %s":                   "Keine Hintergrundbilder mit Tag %s",
+	"Tagged %s":                                 "Tag %s",
+	"Tags across %d wallpapers":                 "Tags aus %d Hintergrundbildern",
+	"(fetching %d more)":                        "(%d werden noch geladen)",
+	"(%d lookups failed)":                       "(%d Abfragen fehlgeschlagen)",
+	"No tags yet":                               "Noch keine Tags",
+	"No tags found":                             "Keine Tags gefunden",
+	"Mark %c set":                               "Marke %c gesetzt",
+	"Mark %c is not set":                        "Marke %c ist nicht gesetzt",
+	"Mark %c is not in these results":           "Marke %c ist nicht in diesen Ergebnissen",
+	"press 1-9":                                 "1-9 drücken",
+	"mark the selection":                        "Auswahl markieren",
+	"jump to a mark":                            "zu einer Marke springen",
+	"%d of %d loaded":                           "%d von %d geladen",
+	"%d per page":                               "%d pro Seite",
+	"seed %s":                                   "Seed %s",
+	"Undo failed: %v":                           "Rückgängig fehlgeschlagen: %v",
+	"undo the last wallpaper change":            "letzten Hintergrundwechsel rückgängig machen",
+	"scroll a screen":                           "eine Bildschirmseite blättern",
+	"first wallpaper":                           "erstes Hintergrundbild",
+	"last loaded wallpaper":                     "letztes geladenes Hintergrundbild",
+	"Browse-only mode: wallpapers can't be set or deleted":            "Nur-Ansehen-Modus: Hintergrundbilder können nicht gesetzt oder gelöscht werden",
+	"toggle sfw, sketchy, nsfw and search again":                      "sfw, sketchy, nsfw umschalten und neu suchen",
+	"toggle general, anime, people and search again":                  "general, anime, people umschalten und neu suchen",
+	"Filters only apply to search results":                            "Filter gelten nur für Suchergebnisse",
+	"At least one must stay selected":                                 "Mindestens eines muss ausgewählt bleiben",
+	"Searching with %s...":                                            "Suche mit %s...",
+	"No wallpapers match %s":                                          "Keine Hintergrundbilder passen zu %s",
+	"next sorting: relevance, toplist, hot, newest, random":           "nächste Sortierung: Relevanz, Topliste, angesagt, neueste, zufällig",
+	"Sorting only applies to search results":                          "Sortierung gilt nur für Suchergebnisse",
+	"Sorting by %s...":                                                "Sortiere nach %s...",
+	"Sorted by %s":                                                    "Sortiert nach %s",
+	"All reviewed - q to finish":                                      "Alles geprüft - q zum Beenden",
+	"%d of %d left  kept %d  deleted %d (%s)  space keeps, d deletes": "%d von %d übrig  %d behalten  %d gelöscht (%s)  Leertaste behält, d löscht",
+	"keep and move on (review)":                                       "behalten und weiter (Review)",
+	"Loading failed: %v":                                              "Laden fehlgeschlagen: %v",
+	"Downloading %s":                                                  "Lade %s herunter",
+	"page %d/%d":                                                      "Seite %d/%d",
+	"i/esc back  o open in browser  u more by uploader":               "i/Esc zurück  o im Browser öffnen  u mehr vom Uploader",
+	"Searching uploads by %s...":                                      "Suche Uploads von %s...",
+	"Uploaded by %s":                                                  "Hochgeladen von %s",
+	"No uploads by %s":                                                "Keine Uploads von %s",
+	"enter set":                                                       "Enter setzen",
+	"find similar wallpapers":                                         "ähnliche Hintergrundbilder finden",
+	"L similar":                                                       "L ähnliche",
+	"Searching for wallpapers like %s...":                             "Suche nach Hintergrundbildern wie %s...",
+	"Similar to %s":                                                   "Ähnlich wie %s",
+	"Nothing similar to %s found":                                     "Nichts Ähnliches zu %s gefunden",
+	"--no-exec: open %s yourself":                                     "--no-exec: %s bitte selbst öffnen",
+	"press 1-5, or 0 to clear":                                        "1-5 drücken, oder 0 zum Löschen",
+	"note:":                                                           "Notiz:",
+	"Saving ratings failed: %v":                                       "Speichern der Bewertungen fehlgeschlagen: %v",
+	"Cleared the rating of %s":                                        "Bewertung von %s gelöscht",
+	"Removed the note on %s":                                          "Notiz zu %s entfernt",
+	"Noted %s":                                                        "Notiz zu %s gespeichert",
+	"Rating":                                                          "Bewertung",
+	"Note":                                                            "Notiz",
+	"rate the selection, 0 clears":                                    "Auswahl bewerten, 0 löscht",
+	"note on the selection":                                           "Notiz zur Auswahl",
+
+	"Downloads in progress - quit anyway? (y/n)": "Downloads laufen noch - trotzdem beenden? (y/n)",
+	"no matches for %q":                          "keine Treffer für %q",
+	"filter:":                                    "Filter:",
+	"filter %q: %d of %d  (esc to clear)":        "Filter %q: %d von %d  (Esc zum Aufheben)",
+	"slideshow %d/%d  %s  %s  (p/esc to stop)":   "Diashow %d/%d  %s  %s  (p/Esc zum Beenden)",
+	"render failed":                              "Darstellung fehlgeschlagen",
+	"Applying %s...":                             "Wende %s an...",
+	"Setting wallpaper: %s":                      "Setze Hintergrund: %s",
+	"Wallpaper set!":                             "Hintergrund gesetzt!",
+
+	// settings
+	"copy your Wallhaven account settings into the config": "Wallhaven-Kontoeinstellungen in die Konfiguration übernehmen",
+	"Wallhaven account settings:":                          "Wallhaven-Kontoeinstellungen:",
+	"Write purity, categories and ratios to %s? [y/N] ":    "Purity, Kategorien und Seitenverhältnisse in %s schreiben? [y/N] ",
+	"Config updated.": "Konfiguration aktualisiert.",
+}
@@ -0,0 +1,315 @@
+package i18n
+
+var es = map[string]string{
+	// usage
+	"Usage:":    "Uso:",
+	"Commands:": "Comandos:",
+	"Flags:":    "Opciones:",
+	"Flags override values from ~/.config/vista/config.yaml.":                                                 "Las opciones sustituyen los valores de ~/.config/vista/config.yaml.",
+	"swww transition type, e.g. fade, wipe, grow":                                                             "tipo de transición de swww, p. ej. fade, wipe, grow",
+	"transition length in seconds":                                                                            "duración de la transición en segundos",
+	"transition origin, e.g. center or 0.8,0.9":                                                               "origen de la transición, p. ej. center o 0.8,0.9",
+	"set a solid colour; two comma-separated colours make a gradient":                                         "aplicar un color sólido; dos colores separados por comas forman un degradado",
+	"browse wallpapers starred with f":                                                                        "explorar los fondos marcados con f",
+	"wallpapers with every tag (-tag excludes, a number is a tag ID)":                                         "fondos con todas las etiquetas (-tag excluye, un número es un ID de etiqueta)",
+	"Queries accept +tag (required), -tag (excluded) and id:N (exact tag ID).":                                "Las búsquedas aceptan +tag (obligatoria), -tag (excluida) e id:N (ID exacto de etiqueta).",
+	"cycle through a playlist (a in the grid queues to \"queue\")":                                            "reproducir una lista (a en la cuadrícula añade a \"queue\")",
+	"index an existing folder (--lookup confirms Wallhaven IDs)":                                              "indexar una carpeta existente (--lookup confirma los ID de Wallhaven)",
+	"list imported wallpapers (--sort rating, --min-rating n)":                                                "listar los fondos importados (--sort rating, --min-rating n)",
+	"find a local image's Wallhaven page by file name or image match":                                         "encontrar la página de Wallhaven de una imagen local por nombre o por comparación",
+	"print the photographer credit for the current wallpaper":                                                 "mostrar el crédito del fotógrafo del fondo actual",
+	"set a wallpaper every --interval (30m), matching --query, picked by --strategy":                          "establecer un fondo cada --interval (30m) según --query, elegido por --strategy",
+	"set a result without opening the grid, then exit (or least-recent, top-unseen, top-rated, palette)":      "establecer un resultado sin abrir la cuadrícula y salir (o least-recent, top-unseen, top-rated, palette)",
+	"with --apply or daemon, print search_started, downloaded, set and error events as JSON lines":            "con --apply o daemon, imprimir los eventos search_started, downloaded, set y error como líneas JSON",
+	"browse downloaded and imported wallpapers offline":                                                       "explorar fondos descargados e importados sin conexión",
+	"make the running daemon change wallpaper now":                                                            "hacer que el daemon cambie de fondo ahora",
+	"print every change with its time, ID and page (--since date)":                                            "mostrar cada cambio con su hora, ID y página (--since fecha)",
+	"print the first page of results with its meta as JSON and exit":                                          "imprimir la primera página de resultados con sus metadatos en JSON y salir",
+	"restore the previous wallpaper; repeat to step further back":                                             "restaurar el fondo anterior; repetir para retroceder más",
+	"browse only: never set or delete wallpapers or run scripts":                                              "solo navegar: nunca establecer ni eliminar fondos ni ejecutar scripts",
+	"play back a grid session saved with --record":                                                            "reproducir una sesión de cuadrícula guardada con --record",
+	"save the grid session as an asciinema cast, for bug reports":                                             "guardar la sesión de cuadrícula como cast de asciinema, para informes de errores",
+	"full-screen photo frame of --query, every --interval (5m), within --hours":                               "marco de fotos a pantalla completa de --query, cada --interval (5m), dentro de --hours",
+	"dominant colour from Wallhaven's palette, e.g. 0066cc or #cc0000":                                        "color dominante de la paleta de Wallhaven, p. ej. 0066cc o #cc0000",
+	"only these exact resolutions e.g. 2560x1440,3840x2160":                                                   "solo estas resoluciones exactas, p. ej. 2560x1440,3840x2160",
+	"download results and print paths, an m3u or a feh/swww/swaybg script (--format)":                         "descargar resultados e imprimir rutas, un m3u o un script de feh/swww/swaybg (--format)",
+	"clean up downloads, largest (or least recently set) first: space keeps, d deletes":                       "limpiar descargas, las más grandes (o las menos usadas) primero: espacio conserva, d borra",
+	"toplist window for top: 1d, 3d, 1w, 1M, 3M, 6M or 1y":                                                    "periodo de la lista top: 1d, 3d, 1w, 1M, 3M, 6M o 1y",
+	"pick from starter searches: minimal, nature, space, anime, dark, city, abstract":                         "elige entre búsquedas iniciales: minimal, nature, space, anime, dark, city, abstract",
+	"wallpapers uploaded by a Wallhaven user, newest first":                                                   "fondos subidos por un usuario de Wallhaven, los más nuevos primero",
+	"serve results from page-N.json files (e.g. saved --json output) instead of Wallhaven":                    "servir resultados desde archivos page-N.json (p. ej. salida --json guardada) en lugar de Wallhaven",
+	"open one Wallhaven wallpaper by ID or link; enter sets it":                                               "abrir un fondo de Wallhaven por ID o enlace; Enter lo aplica",
+	"set the wallpaper of the day for --query; the same one all day":                                          "aplicar el fondo del día para --query; el mismo todo el día",
+	"never run external programs: built-in renderers and wallpaper setting only, no scripts, swww or browser": "no ejecutar nunca programas externos: solo renderizadores integrados y aplicación del fondo, sin scripts, swww ni navegador",
+	"image posts from a subreddit, --sort hot, new, top or rising, --time for top":                            "publicaciones con imágenes de un subreddit, --sort hot, new, top o rising, --time para top",
+	"rate a wallpaper or file 0-5 (0 clears), then an optional note":                                          "valorar un fondo o archivo de 0 a 5 (0 borra), seguido de una nota opcional",
+	"write a shareable HTML gallery of the results to --out (gallery.html)":                                   "escribir una galería HTML para compartir con los resultados en --out (gallery.html)",
+	"copy new downloads to another machine over ssh (default sync_remote)":                                    "copiar las descargas nuevas a otra máquina por ssh (por defecto sync_remote)",
+	"copy new wallpapers from another machine into download_dir":                                              "copiar los fondos nuevos de otra máquina a download_dir",
+	"NASA's picture of the day, or --date, --from/--to a range, --random n":                                   "la imagen del día de la NASA, o --date, --from/--to un intervalo, --random n",
+
+	"search by keyword":    "buscar por palabra clave",
+	"top-rated wallpapers": "fondos mejor valorados",
+	"trending wallpapers":  "fondos en tendencia",
+	"newest wallpapers":    "fondos más recientes",
+	"random wallpapers":    "fondos aleatorios",
+	"browse wallpapers you have set, most recent first": "ver los fondos que has usado, los más recientes primero",
+	"list your Wallhaven collections (needs --apikey)":  "listar tus colecciones de Wallhaven (requiere --apikey)",
+
+	"Wallhaven API key":                                                    "clave de la API de Wallhaven",
+	"comma-separated: sfw,sketchy,nsfw":                                    "separado por comas: sfw,sketchy,nsfw",
+	"comma-separated: general,anime,people":                                "separado por comas: general,anime,people",
+	"minimum resolution e.g. 1920x1080":                                    "resolución mínima, p. ej. 1920x1080",
+	"comma-separated aspect ratios e.g. 16x9,16x10, or landscape/portrait": "proporciones separadas por comas, p. ej. 16x9,16x10, o landscape/portrait",
+	"directory to save wallpapers":                                         "directorio donde guardar los fondos",
+	"script to run instead of setting the wallpaper; {path}, {id}, {url}, {monitor} are substituted": "script a ejecutar en lugar de aplicar el fondo; se sustituyen {path}, {id}, {url}, {monitor}",
+	"ask before quitting while downloads are in flight":                                              "preguntar antes de salir si hay descargas en curso",
+	"exit after N minutes without input (0 disables)":                                                "salir tras N minutos sin actividad (0 lo desactiva)",
+	"Enter sets the wallpaper without leaving the grid":                                              "Enter aplica el fondo sin salir de la cuadrícula",
+	"seconds per wallpaper in the 'p' slideshow preview":                                             "segundos por fondo en la presentación ('p')",
+	"grid thumbnail source: small, large or original":                                                "miniatura a usar: small, large u original",
+	"image renderer: auto, chafa, kitty, sixel, iterm, ueberzug":                                     "renderizador de imágenes: auto, chafa, kitty, sixel, iterm, ueberzug",
+	"plain ASCII UI (no box drawing or block characters)":                                            "interfaz solo ASCII (sin marcos ni bloques)",
+	"append diagnostics (render failures etc.) to this file":                                         "añadir diagnósticos (fallos de renderizado, etc.) a este archivo",
+	"print progress messages":                                                                        "mostrar mensajes de progreso",
+
+	// main
+	"Unknown command: %q":                                            "Comando desconocido: %q",
+	"Error: %v":                                                      "Error: %v",
+	"Error reading history: %v":                                      "Error al leer el historial: %v",
+	"Warning: could not load config: %v":                             "Aviso: no se pudo cargar la configuración: %v",
+	"No downloaded wallpapers found.":                                "No se encontraron fondos descargados.",
+	"Found %d downloaded wallpapers. Loading...":                     "Encontrados %d fondos descargados. Cargando...",
+	"Searching for %q":                                               "Buscando %q",
+	"Fetching top wallpapers":                                        "Obteniendo los fondos mejor valorados",
+	"Fetching hot wallpapers":                                        "Obteniendo fondos en tendencia",
+	"Fetching new wallpapers":                                        "Obteniendo fondos nuevos",
+	"Fetching random wallpapers":                                     "Obteniendo fondos aleatorios",
+	"No results found.":                                              "No se encontraron resultados.",
+	"Found %d wallpapers across %d pages. Loading...":                "Encontrados %d fondos en %d páginas. Cargando...",
+	"Warning: chafa not found, falling back to placeholder renderer": "Aviso: chafa no encontrado, se usarán marcadores de posición",
+	"Invalid border style %q: use %s":                                "Estilo de borde no válido %q: usa %s",
+	"Invalid thumb size %q: use small, large or original":            "Tamaño de miniatura no válido %q: usa small, large u original",
+	"Error opening log file: %v":                                     "Error al abrir el archivo de registro: %v",
+	"%-8d %-30s %d wallpapers":                                       "%-8d %-30s %d fondos",
+	"set needs --color '#rrggbb' or --color '#top,#bottom'":          "set necesita --color '#rrggbb' o --color '#arriba,#abajo'",
+	"set takes at most two colours":                                  "set admite como máximo dos colores",
+	"No favorites yet - press f in the grid to add one.":             "Aún no hay favoritos: pulsa f en la cuadrícula para añadir uno.",
+	"Fetching wallpapers tagged %s":                                  "Obteniendo fondos con la etiqueta %s",
+	"Skipping %s: %v":                                                "Omitiendo %s: %v",
+	"Playing %s for %s":                                              "Mostrando %s durante %s",
+	"library needs a subcommand: import, list or rate":               "library necesita un subcomando: import, list o rate",
+	"library import needs a directory":                               "library import necesita un directorio",
+	"Added %d, unchanged %d, %d duplicates":                          "%d añadidos, %d sin cambios, %d duplicados",
+	"duplicate:":                                                     "duplicado:",
+	"unknown library subcommand %q":                                  "subcomando de library desconocido %q",
+	"Confirmed %d Wallhaven IDs":                                     "%d ID de Wallhaven confirmados",
+	"lookup needs exactly one file":                                  "lookup necesita exactamente un archivo",
+	"File name ID %s not found, comparing images instead":            "ID %s del nombre no encontrado, comparando imágenes",
+	"no matching wallpaper found on Wallhaven":                       "no se encontró ningún fondo coincidente en Wallhaven",
+	"image match, %d/64 bits differ":                                 "coincidencia de imagen, %d/64 bits distintos",
+	"Searching %s wallpapers with colour #%s...":                     "Buscando fondos de %s con color #%s...",
+	"no current wallpaper recorded":                                  "no hay ningún fondo actual registrado",
+	"No attribution recorded for this wallpaper":                     "No hay atribución registrada para este fondo",
+	"Author":                                                              "Autor",
+	"Profile":                                                             "Perfil",
+	"--interval must be positive":                                         "--interval debe ser positivo",
+	"Set %s, next change in %s":                                           "%s establecido, próximo cambio en %s",
+	"Daemon stopped":                                                      "Daemon detenido",
+	"no wallpapers match the query":                                       "ningún fondo coincide con la búsqueda",
+	"invalid --apply %q: use first or %s":                                 "--apply %q no válido: usa first o %s",
+	"Wallpaper set: %s":                                                   "Fondo establecido: %s",
+	"invalid --events %q: use json":                                       "--events %q no válido: usa json",
+	"unknown daemon command %q":                                           "comando de daemon desconocido %q",
+	"a daemon is already running (%s)":                                    "ya hay un daemon en ejecución (%s)",
+	"usage: vista ctl next":                                               "uso: vista ctl next",
+	"no daemon is running; start one with vista daemon":                   "no hay ningún daemon en ejecución; inicia uno con vista daemon",
+	"No wallpaper has been set yet.":                                      "Todavía no se ha establecido ningún fondo.",
+	"no wallpaper has been set yet":                                       "todavía no se ha establecido ningún fondo",
+	"Found %d wallpapers in your history. Loading...":                     "Encontrados %d fondos en tu historial. Cargando...",
+	"invalid --since %q: use YYYY-MM-DD":                                  "--since %q no válido: usa AAAA-MM-DD",
+	"nothing to undo":                                                     "nada que deshacer",
+	"Restored %s":                                                         "%s restaurado",
+	"%s sets wallpapers, which --browse-only disables":                    "%s establece fondos, algo que --browse-only desactiva",
+	"purity is locked to sfw":                                             "la pureza está bloqueada en sfw",
+	"usage: vista replay <file.cast>":                                     "uso: vista replay <archivo.cast>",
+	"invalid --hours %q: use from-to, e.g. 7-23":                          "--hours %q no válido: usa desde-hasta, p. ej. 7-23",
+	"unknown --format %q: use %s":                                         "--format %q desconocido: usa %s",
+	"-n must be at least 1":                                               "-n debe ser al menos 1",
+	"Downloading %d wallpapers to %s...":                                  "Descargando %d fondos en %s...",
+	"no wallpapers could be downloaded":                                   "no se pudo descargar ningún fondo",
+	"%d downloads failed":                                                 "%d descargas fallidas",
+	"unknown --sort %q: use size or set":                                  "--sort %q desconocido: usa size o set",
+	"Kept %d, deleted %d, reclaimed %.1f MB":                              "%d conservados, %d borrados, %.1f MB recuperados",
+	"unknown --strategy %q: use %s":                                       "--strategy %q desconocida: usa %s",
+	"Invalid selection style %q: use %s":                                  "Estilo de selección no válido %q: usa %s",
+	"Warning: search failed (%v), using the local library":                "Aviso: la búsqueda falló (%v), usando la biblioteca local",
+	"Invalid fallback %q: use %s":                                         "Alternativa no válida %q: usa %s",
+	"Pick a starting point:":                                              "Elige un punto de partida:",
+	"Number or name [1-%d]: ":                                             "Número o nombre [1-%d]: ",
+	"unknown collection %q: use %s":                                       "colección desconocida %q: usa %s",
+	"Fetching %s wallpapers":                                              "Obteniendo fondos de %s",
+	"clean, simple shapes and flat colour":                                "formas limpias y simples y colores planos",
+	"landscapes, forests, mountains and water":                            "paisajes, bosques, montañas y agua",
+	"planets, nebulae and starfields":                                     "planetas, nebulosas y campos de estrellas",
+	"anime and illustration":                                              "anime e ilustración",
+	"dark, low-light wallpapers":                                          "fondos oscuros y con poca luz",
+	"cityscapes and architecture":                                         "paisajes urbanos y arquitectura",
+	"abstract art and patterns":                                           "arte abstracto y patrones",
+	"Fetching uploads by %s":                                              "Obteniendo subidas de %s",
+	"unknown --source %q: use fixture:<dir>":                              "--source desconocido %q: usa fixture:<dir>",
+	"Warning: could not fetch account settings: %v":                       "Aviso: no se pudieron obtener los ajustes de la cuenta: %v",
+	"not a Wallhaven ID or URL: %q":                                       "no es un ID ni una URL de Wallhaven: %q",
+	"id needs exactly one wallpaper ID or URL":                            "id necesita exactamente un ID o URL de fondo",
+	"Already set today: %s":                                               "Ya aplicado hoy: %s",
+	"Wallpaper of the day: %s":                                            "Fondo del día: %s",
+	"Warning: --no-exec: not running script %q":                           "Aviso: --no-exec: no se ejecuta el script %q",
+	"Warning: --no-exec: not running %s, using a built-in renderer":       "Aviso: --no-exec: no se ejecuta %s, se usa un renderizador integrado",
+	"reddit needs a subreddit, e.g. vista reddit wallpapers":              "reddit necesita un subreddit, p. ej. vista reddit wallpapers",
+	"invalid min resolution %q: use WxH":                                  "resolución mínima no válida %q: usa WxH",
+	"Fetching image posts from r/%s":                                      "Obteniendo publicaciones con imágenes de r/%s",
+	"unknown sort %q: use rating":                                         "orden desconocido %q: usa rating",
+	"library rate needs a wallpaper and a rating from 0 to 5":             "library rate necesita un fondo y una valoración de 0 a 5",
+	"invalid rating %q: use 0 to %d":                                      "valoración no válida %q: usa de 0 a %d",
+	"%s is not in the library or download folder":                         "%s no está en la biblioteca ni en la carpeta de descargas",
+	"Rated %s %d/%d":                                                      "%s valorado con %d/%d",
+	"every match is rated below %d":                                       "todos los resultados tienen una valoración inferior a %d",
+	"Fetching %d thumbnails...":                                           "Descargando %d miniaturas...",
+	"no thumbnails could be downloaded":                                   "no se pudo descargar ninguna miniatura",
+	"%d thumbnails failed, leaving them out":                              "fallaron %d miniaturas; se omiten",
+	"Wrote %d wallpapers to %s":                                           "%d fondos escritos en %s",
+	"%s needs a remote: [user@]host[:path], or sync_remote in the config": "%s necesita un destino: [user@]host[:path], o sync_remote en la configuración",
+	"--no-exec: %s needs ssh":                                             "--no-exec: %s necesita ssh",
+	"Copied %d wallpapers, %d already up to date":                         "%d fondos copiados, %d ya actualizados",
+	"%d files could not be copied":                                        "no se pudieron copiar %d archivos",
+	"invalid --%s %q: use YYYY-MM-DD":                                     "--%s no válido %q: usa AAAA-MM-DD",
+	"Fetching the Astronomy Picture of the Day":                           "Obteniendo la imagen astronómica del día",
+	"No pictures found; videos are skipped.":                              "No se encontraron imágenes; los vídeos se omiten.",
+
+	// grid
+	"KEYS":                               "TECLAS",
+	"navigate":                           "moverse",
+	"download + set":                     "descargar + aplicar",
+	"set (stay open)":                    "aplicar (sin salir)",
+	"open in browser":                    "abrir en el navegador",
+	"jump to random":                     "saltar a uno aleatorio",
+	"slideshow preview":                  "vista de presentación",
+	"delete (local files)":               "borrar (archivos locales)",
+	"filter loaded results":              "filtrar resultados cargados",
+	"clear filter":                       "quitar filtro",
+	"toggle help":                        "mostrar/ocultar ayuda",
+	"quit":                               "salir",
+	"Failed to set %s: %v":               "No se pudo aplicar %s: %v",
+	"try on desktop, revert unless kept": "probar en el escritorio, revertir si no se confirma",
+	"Kept %s":                            "Se mantiene %s",
+	"No previous wallpaper to revert to": "No hay un fondo anterior al que volver",
+	"Failed to revert: %v":               "Error al revertir: %v",
+	"Reverted to the previous wallpaper": "Se restauró el fondo anterior",
+	"Trying %s - enter keeps it, esc reverts (%ds)": "Probando %s - enter lo mantiene, esc lo revierte (%ds)",
+	"toggle favorite":                  "marcar/desmarcar favorito",
+	"Saving favorites failed: %v":      "Error al guardar favoritos: %v",
+	"Added %s to favorites":            "%s añadido a favoritos",
+	"Removed %s from favorites":        "%s eliminado de favoritos",
+	"add to the play queue":            "añadir a la cola",
+	"Saving playlist failed: %v":       "Error al guardar la lista: %v",
+	"Queued %s (%d in queue)":          "%s en cola (%d en la cola)",
+	"details":                          "detalles",
+	"no metadata source for this list": "no hay fuente de metadatos para esta lista",
+	"Resolution":                       "Resolución",
+	"Page":                             "Página",
+	"File":                             "Archivo",
+	"Size":                             "Tamaño",
+	"Type":                             "Tipo",
+	"Category":                         "Categoría",
+	"Purity":                           "Pureza",
+	"Uploader":                         "Subido por",
+	"Uploaded":                         "Subido el",
+	"Views":                            "Vistas",
+	"Favorites":                        "Favoritos",
+	"Source":                           "Fuente",
+	"Colors":                           "Colores",
+	"Tags":                             "Etiquetas",
+	"Details unavailable: %v":          "Detalles no disponibles: %v",
+	"Loading details...":               "Cargando detalles...",
+	"i/esc back  o open in browser":    "i/esc volver  o abrir en el navegador",
+	"tag cloud of loaded results":      "nube de etiquetas de los resultados cargados",
+	"arrows move  enter search tag  c/esc back": "flechas mover  enter buscar etiqueta  c/esc volver",
+	"Searching isn't available from this list":  "La búsqueda no está disponible en esta lista",
+	"Searching for tag %s...":                   "Buscando la etiqueta %s...",
+	"Search failed: %v":                         "La búsqueda falló: %v",
+	"No wallpapers tagged %s":                   "No hay fondos con la etiqueta %s",
+	"Tagged %s":                                 "Etiqueta %s",
+	"Tags across %d wallpapers":                 "Etiquetas de %d fondos",
+	"(fetching %d more)":                        "(cargando %d más)",
+	"(%d lookups failed)":                       "(%d consultas fallidas)",
+	"No tags yet":                               "Aún no hay etiquetas",
+	"No tags found":                             "No se encontraron etiquetas",
+	"Mark %c set":                               "Marca %c establecida",
+	"Mark %c is not set":                        "La marca %c no está establecida",
+	"Mark %c is not in these results":           "La marca %c no está en estos resultados",
+	"press 1-9":                                 "pulsa 1-9",
+	"mark the selection":                        "marcar la selección",
+	"jump to a mark":                            "saltar a una marca",
+	"%d of %d loaded":                           "%d de %d cargados",
+	"%d per page":                               "%d por página",
+	"seed %s":                                   "semilla %s",
+	"Undo failed: %v":                           "No se pudo deshacer: %v",
+	"undo the last wallpaper change":            "deshacer el último cambio de fondo",
+	"scroll a screen":                           "desplazar una pantalla",
+	"first wallpaper":                           "primer fondo",
+	"last loaded wallpaper":                     "último fondo cargado",
+	"Browse-only mode: wallpapers can't be set or deleted":            "Modo solo navegación: no se pueden establecer ni eliminar fondos",
+	"toggle sfw, sketchy, nsfw and search again":                      "alternar sfw, sketchy, nsfw y volver a buscar",
+	"toggle general, anime, people and search again":                  "alternar general, anime, people y volver a buscar",
+	"Filters only apply to search results":                            "Los filtros solo se aplican a resultados de búsqueda",
+	"At least one must stay selected":                                 "Al menos uno debe quedar seleccionado",
+	"Searching with %s...":                                            "Buscando con %s...",
+	"No wallpapers match %s":                                          "Ningún fondo coincide con %s",
+	"next sorting: relevance, toplist, hot, newest, random":           "siguiente orden: relevancia, top, popular, recientes, aleatorio",
+	"Sorting only applies to search results":                          "El orden solo se aplica a resultados de búsqueda",
+	"Sorting by %s...":                                                "Ordenando por %s...",
+	"Sorted by %s":                                                    "Ordenado por %s",
+	"All reviewed - q to finish":                                      "Todo revisado - q para terminar",
+	"%d of %d left  kept %d  deleted %d (%s)  space keeps, d deletes": "quedan %d de %d  %d conservados  %d borrados (%s)  espacio conserva, d borra",
+	"keep and move on (review)":                                       "conservar y seguir (revisión)",
+	"Loading failed: %v":                                              "Error al cargar: %v",
+	"Downloading %s":                                                  "Descargando %s",
+	"page %d/%d":                                                      "página %d/%d",
+	"i/esc back  o open in browser  u more by uploader":               "i/esc volver  o abrir en el navegador  u más del autor",
+	"Searching uploads by %s...":                                      "Buscando subidas de %s...",
+	"Uploaded by %s":                                                  "Subido por %s",
+	"No uploads by %s":                                                "No hay subidas de %s",
+	"enter set":                                                       "Enter aplicar",
+	"find similar wallpapers":                                         "buscar fondos similares",
+	"L similar":                                                       "L similares",
+	"Searching for wallpapers like %s...":                             "Buscando fondos como %s...",
+	"Similar to %s":                                                   "Similares a %s",
+	"Nothing similar to %s found":                                     "No se encontró nada similar a %s",
+	"--no-exec: open %s yourself":                                     "--no-exec: abre %s tú mismo",
+	"press 1-5, or 0 to clear":                                        "pulsa 1-5, o 0 para borrar",
+	"note:":                                                           "nota:",
+	"Saving ratings failed: %v":                                       "no se pudieron guardar las valoraciones: %v",
+	"Cleared the rating of %s":                                        "valoración de %s borrada",
+	"Removed the note on %s":                                          "nota de %s eliminada",
+	"Noted %s":                                                        "nota de %s guardada",
+	"Rating":                                                          "Valoración",
+	"Note":                                                            "Nota",
+	"rate the selection, 0 clears":                                    "valorar la selección, 0 borra",
+	"note on the selection":                                           "nota sobre la selección",
+
+	"Downloads in progress - quit anyway? (y/n)": "Hay descargas en curso - ¿salir de todos modos? (y/n)",
+	"no matches for %q":                          "sin coincidencias para %q",
+	"filter:":                                    "filtro:",
+	"filter %q: %d of %d  (esc to clear)":        "filtro %q: %d de %d  (esc para quitar)",
+	"slideshow %d/%d  %s  %s  (p/esc to stop)":   "presentación %d/%d  %s  %s  (p/esc para parar)",
+	"render failed":                              "error al renderizar",
+	"Applying %s...":                             "Aplicando %s...",
+	"Setting wallpaper: %s":                      "Aplicando fondo: %s",
+	"Wallpaper set!":                             "¡Fondo aplicado!",
+
+	// settings
+	"copy your Wallhaven account settings into the config": "copiar los ajustes de tu cuenta de Wallhaven a la configuración",
+	"Wallhaven account settings:":                          "Ajustes de la cuenta de Wallhaven:",
+	"Write purity, categories and ratios to %s? [y/N] ":    "¿Escribir pureza, categorías y proporciones en %s? [y/N] ",
+	"Config updated.": "Configuración actualizada.",
+}
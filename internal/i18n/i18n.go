@@ -0,0 +1,47 @@
+// Package i18n translates user-facing strings. Messages are keyed by their
+// English text, so untranslated strings fall back to English unchanged.
+package i18n
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// catalogs maps a language code to its translations.
+var catalogs = map[string]map[string]string{
+	"de": de,
+	"es": es,
+}
+
+var (
+	once    sync.Once
+	current map[string]string
+)
+
+// T returns the translation of msg for the current locale, or msg itself.
+func T(msg string) string {
+	once.Do(func() { current = catalogs[Lang()] })
+	if t, ok := current[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Lang returns the two-letter language code from the first non-empty of
+// $LC_ALL, $LC_MESSAGES and $LANG, e.g. "de" for "de_DE.UTF-8".
+func Lang() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		if v == "C" || v == "POSIX" {
+			return "en"
+		}
+		lang, _, _ := strings.Cut(v, "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		return strings.ToLower(lang)
+	}
+	return "en"
+}
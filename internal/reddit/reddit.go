@@ -0,0 +1,238 @@
+// Package reddit pages through the image posts of subreddits such as
+// r/wallpapers or r/EarthPorn, using Reddit's public JSON listings. Only
+// posts linking straight to an image file are kept.
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/davenicholson-xyz/vista/internal/retry"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// Sorts are the listing orders Reddit offers.
+var Sorts = []string{"hot", "new", "top", "rising"}
+
+// Times are the windows of the top sort.
+var Times = []string{"hour", "day", "week", "month", "year", "all"}
+
+// pageSize is how many posts are asked for per page, Reddit's maximum.
+const pageSize = 100
+
+// listingRoot is where subreddit listings are served.
+var listingRoot = "https://www.reddit.com"
+
+// imageExts are the extensions of direct image links.
+var imageExts = []string{".jpg", ".jpeg", ".png"}
+
+// titleSize matches the "[3840x2160]" size most wallpaper subreddits ask
+// posters to put in the title.
+var titleSize = regexp.MustCompile(`(\d{3,5})\s*[x×X]\s*(\d{3,5})`)
+
+// Source is a provider.ContextProvider over one or more subreddits.
+type Source struct {
+	// Subreddit is the name without r/; several may be joined with +.
+	Subreddit string
+	// Sort is one of Sorts; empty means hot. Time is the window of the top
+	// sort, one of Times; empty means day.
+	Sort string
+	Time string
+	// MinWidth and MinHeight skip posts whose image is known to be
+	// smaller. Posts of unknown size are kept.
+	MinWidth, MinHeight int
+	// NSFW keeps posts marked over 18.
+	NSFW bool
+	// UserAgent is sent with every request; Reddit throttles generic ones.
+	UserAgent string
+	// Retries is how many times a request that fails transiently is
+	// repeated.
+	Retries int
+
+	mu    sync.Mutex
+	after map[int]string // listing cursor that page starts after
+}
+
+// Validate checks the sort and time window.
+func (s *Source) Validate() error {
+	if s.Subreddit == "" {
+		return fmt.Errorf("no subreddit given")
+	}
+	if s.Sort != "" && !slices.Contains(Sorts, s.Sort) {
+		return fmt.Errorf("unknown sort %q: use %s", s.Sort, strings.Join(Sorts, ", "))
+	}
+	if s.Time != "" && !slices.Contains(Times, s.Time) {
+		return fmt.Errorf("unknown time %q: use %s", s.Time, strings.Join(Times, ", "))
+	}
+	return nil
+}
+
+// Page returns page of the listing.
+func (s *Source) Page(page int) ([]provider.Wallpaper, provider.Meta, error) {
+	return s.PageContext(context.Background(), page)
+}
+
+// PageContext is Page with a context. Reddit pages by cursor, so a page can
+// only be fetched after the one before it; Meta.LastPage is the next page
+// while the listing goes on, and Total and PerPage are unknown.
+func (s *Source) PageContext(ctx context.Context, page int) ([]provider.Wallpaper, provider.Meta, error) {
+	s.mu.Lock()
+	after, ok := s.after[page]
+	s.mu.Unlock()
+	if page > 1 && !ok {
+		return nil, provider.Meta{}, fmt.Errorf("page %d requested before page %d", page, page-1)
+	}
+
+	sort := s.Sort
+	if sort == "" {
+		sort = "hot"
+	}
+	params := url.Values{"limit": {fmt.Sprint(pageSize)}, "raw_json": {"1"}}
+	if sort == "top" {
+		params.Set("t", s.Time)
+		if s.Time == "" {
+			params.Set("t", "day")
+		}
+	}
+	if after != "" {
+		params.Set("after", after)
+	}
+	endpoint := fmt.Sprintf("%s/r/%s/%s.json?%s", listingRoot, url.PathEscape(s.Subreddit), sort, params.Encode())
+
+	var listing struct {
+		Data struct {
+			After    string `json:"after"`
+			Children []struct {
+				Data post `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	if err := s.getJSON(ctx, endpoint, &listing); err != nil {
+		return nil, provider.Meta{}, fmt.Errorf("r/%s: %w", s.Subreddit, err)
+	}
+
+	var wallpapers []provider.Wallpaper
+	for _, c := range listing.Data.Children {
+		if wp, ok := s.wallpaper(c.Data); ok {
+			wallpapers = append(wallpapers, wp)
+		}
+	}
+	meta := provider.Meta{CurrentPage: page, LastPage: page}
+	if listing.Data.After != "" {
+		meta.LastPage = page + 1
+		s.mu.Lock()
+		if s.after == nil {
+			s.after = map[int]string{}
+		}
+		s.after[page+1] = listing.Data.After
+		s.mu.Unlock()
+	}
+	return wallpapers, meta, nil
+}
+
+// post is the part of a Reddit post vista reads.
+type post struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Permalink string `json:"permalink"`
+	Author    string `json:"author"`
+	Over18    bool   `json:"over_18"`
+	Preview   struct {
+		Images []struct {
+			Source      image   `json:"source"`
+			Resolutions []image `json:"resolutions"`
+		} `json:"images"`
+	} `json:"preview"`
+}
+
+type image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// wallpaper converts p, reporting false when it isn't a direct image link,
+// is too small or is over 18 without NSFW.
+func (s *Source) wallpaper(p post) (provider.Wallpaper, bool) {
+	u, err := url.Parse(p.URL)
+	if err != nil || !slices.Contains(imageExts, strings.ToLower(path.Ext(u.Path))) {
+		return provider.Wallpaper{}, false
+	}
+	if p.Over18 && !s.NSFW {
+		return provider.Wallpaper{}, false
+	}
+
+	var w, h int
+	var previews []image
+	if len(p.Preview.Images) > 0 {
+		img := p.Preview.Images[0]
+		w, h = img.Source.Width, img.Source.Height
+		previews = img.Resolutions
+	}
+	if w == 0 || h == 0 {
+		if m := titleSize.FindStringSubmatch(p.Title); m != nil {
+			fmt.Sscan(m[1], &w) //nolint:errcheck
+			fmt.Sscan(m[2], &h) //nolint:errcheck
+		}
+	}
+	if w > 0 && h > 0 && (w < s.MinWidth || h < s.MinHeight) {
+		return provider.Wallpaper{}, false
+	}
+
+	wp := provider.Wallpaper{
+		ID:     p.ID,
+		URL:    listingRoot + p.Permalink,
+		Path:   p.URL,
+		Thumbs: provider.Thumbs{Small: p.URL, Large: p.URL, Original: p.URL},
+		Source: "Reddit",
+	}
+	if w > 0 && h > 0 {
+		wp.Resolution = fmt.Sprintf("%dx%d", w, h)
+	}
+	if p.Author != "" && p.Author != "[deleted]" {
+		wp.Author = "u/" + p.Author
+		wp.AuthorURL = listingRoot + "/user/" + p.Author
+	}
+	// Previews come smallest first; take the first wide enough for a
+	// grid cell, and for the detail view.
+	if i := slices.IndexFunc(previews, func(r image) bool { return r.Width >= 320 }); i >= 0 {
+		wp.Thumbs.Small = previews[i].URL
+	}
+	if i := slices.IndexFunc(previews, func(r image) bool { return r.Width >= 960 }); i >= 0 {
+		wp.Thumbs.Large = previews[i].URL
+	}
+	return wp, true
+}
+
+func (s *Source) getJSON(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if s.UserAgent != "" {
+		req.Header.Set("User-Agent", s.UserAgent)
+	}
+	resp, err := retry.Do(http.DefaultClient, req, s.Retries)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("reddit returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+var _ provider.ContextProvider = (*Source)(nil)
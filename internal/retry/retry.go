@@ -0,0 +1,70 @@
+// Package retry repeats idempotent HTTP requests that fail transiently,
+// backing off exponentially with jitter between attempts.
+package retry
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default is the number of retries callers use unless configured
+// otherwise.
+const Default = 3
+
+// Backoff timing: the first retry waits about baseDelay, each later one
+// twice as long, never more than maxDelay.
+const (
+	baseDelay = 500 * time.Millisecond
+	maxDelay  = 30 * time.Second
+)
+
+// Do sends req with c, retrying up to retries more times on network errors,
+// 429 Too Many Requests and 5xx gateway and availability errors. A
+// Retry-After in seconds is honoured up to maxDelay. req must have no body.
+// The last response or error is returned as is.
+func Do(c *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.Do(req)
+		if attempt >= retries || !transient(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		wait := delay(attempt)
+		if resp != nil {
+			if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+				wait = min(time.Duration(s)*time.Second, maxDelay)
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// transient reports whether a request that ended in resp or err is worth
+// repeating.
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// delay is the wait before retry n (from 0): baseDelay doubled n times,
+// capped at maxDelay, then spread by up to half either way so clients that
+// failed together don't retry together.
+func delay(n int) time.Duration {
+	d := min(baseDelay<<min(n, 16), maxDelay)
+	return d/2 + time.Duration(rand.Int64N(int64(d)))
+}
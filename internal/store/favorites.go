@@ -0,0 +1,113 @@
+package store
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// Favorites is the set of wallpapers starred with 'f', newest first. It is
+// safe for concurrent use.
+type Favorites struct {
+	journal *journal
+	mu      sync.Mutex
+	items   []provider.Wallpaper
+}
+
+// favoriteRecord is one journaled change to the favorites: Add stars a
+// wallpaper, Remove unstars the one with that key.
+type favoriteRecord struct {
+	Add    *provider.Wallpaper `json:"add,omitempty"`
+	Remove string              `json:"remove,omitempty"`
+}
+
+// OpenFavorites loads the favorites file at path, which needn't exist yet,
+// recovering any changes journaled before a crash.
+func OpenFavorites(path string) (*Favorites, error) {
+	f := &Favorites{}
+	j, err := openJournal(path, &f.items, f.replay)
+	if err != nil {
+		return nil, err
+	}
+	f.journal = j
+	return f, nil
+}
+
+// replay applies a journaled record. Starring something already starred, or
+// unstarring something that isn't, is a no-op, so records the snapshot
+// already holds replay harmlessly.
+func (f *Favorites) replay(data json.RawMessage) error {
+	var rec favoriteRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+	switch {
+	case rec.Add != nil:
+		if f.index(*rec.Add) < 0 {
+			f.items = slices.Insert(f.items, 0, *rec.Add)
+		}
+	case rec.Remove != "":
+		if i := f.index(provider.Wallpaper{Path: rec.Remove}); i >= 0 {
+			f.items = slices.Delete(f.items, i, i+1)
+		}
+	}
+	return nil
+}
+
+// Has reports whether wp is a favorite. Wallpapers match by the name they
+// are downloaded under, so a remote result and its downloaded copy are the
+// same favorite, but two providers' photo.jpg are not.
+func (f *Favorites) Has(wp provider.Wallpaper) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.index(wp) >= 0
+}
+
+// Toggle stars or unstars wp, journals the change and reports whether wp is
+// now a favorite.
+func (f *Favorites) Toggle(wp provider.Wallpaper) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i := f.index(wp); i >= 0 {
+		f.items = slices.Delete(f.items, i, i+1)
+		return false, f.journal.record(favoriteRecord{Remove: wallpaper.SavedKey(wp.Path, wp.Source, wp.ID)}, f.items)
+	}
+	f.items = slices.Insert(f.items, 0, wp)
+	return true, f.journal.record(favoriteRecord{Add: &wp}, f.items)
+}
+
+// List returns a copy of the favorites, newest first.
+func (f *Favorites) List() []provider.Wallpaper {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.items)
+}
+
+// adopt adds the wallpapers that aren't favorites yet after the existing
+// ones, and writes the result straight to the snapshot, as the journal
+// only records additions at the front.
+func (f *Favorites) adopt(wps []provider.Wallpaper) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changed := false
+	for _, wp := range wps {
+		if f.index(wp) < 0 {
+			f.items = append(f.items, wp)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return f.journal.compact(f.items)
+}
+
+func (f *Favorites) index(wp provider.Wallpaper) int {
+	key := wallpaper.SavedKey(wp.Path, wp.Source, wp.ID)
+	return slices.IndexFunc(f.items, func(w provider.Wallpaper) bool {
+		return wallpaper.SavedKey(w.Path, w.Source, w.ID) == key
+	})
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+func TestFavoritesSharedBaseName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	f, err := OpenFavorites(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsplash := provider.Wallpaper{ID: "Ab3x", Source: "Unsplash", Path: "https://images.example.com/a/photo.jpg"}
+	pexels := provider.Wallpaper{ID: "991", Source: "Pexels", Path: "https://cdn.example.org/b/photo.jpg"}
+
+	if starred, err := f.Toggle(unsplash); err != nil || !starred {
+		t.Fatalf("Toggle(unsplash) = %v, %v; want true", starred, err)
+	}
+	if f.Has(pexels) {
+		t.Error("another provider's photo.jpg is a favorite")
+	}
+	if starred, err := f.Toggle(pexels); err != nil || !starred {
+		t.Fatalf("Toggle(pexels) = %v, %v; want true", starred, err)
+	}
+
+	// The downloaded copy is the same favorite as the remote result.
+	local := provider.Wallpaper{Path: "/home/me/Pictures/wallpapers/unsplash-Ab3x-photo.jpg"}
+	if !f.Has(local) {
+		t.Error("downloaded copy of a favorite isn't a favorite")
+	}
+
+	if starred, err := f.Toggle(pexels); err != nil || starred {
+		t.Fatalf("second Toggle(pexels) = %v, %v; want false", starred, err)
+	}
+	f, err = OpenFavorites(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Has(unsplash) || f.Has(pexels) {
+		t.Errorf("after reopening: unsplash %v, pexels %v; want true, false", f.Has(unsplash), f.Has(pexels))
+	}
+}
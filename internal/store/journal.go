@@ -0,0 +1,108 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// journalCompact is how many records a journal collects before they are
+// folded into a fresh snapshot.
+const journalCompact = 64
+
+// journal makes a store's mutations durable without rewriting the whole file
+// on every change. Each mutation is appended to path+".journal" as one JSON
+// line and synced to disk; every journalCompact records the full state is
+// written to path with save and the journal is emptied.
+//
+// Opening replays the journal over the snapshot. A crash mid-append leaves
+// at most an unterminated last line, which is dropped, and a crash mid-
+// compaction leaves records the snapshot already holds, so apply must be
+// idempotent.
+type journal struct {
+	path    string
+	records int // appended since the last compaction
+}
+
+// openJournal loads the snapshot at path into v, replays the journal
+// through apply and, if it held anything, compacts it straight away so the
+// next start begins clean. Replay stops at the first record apply rejects;
+// everything after it is treated as damaged.
+func openJournal(path string, v any, apply func(json.RawMessage) error) (*journal, error) {
+	if err := load(path, v); err != nil {
+		return nil, err
+	}
+	j := &journal{path: path}
+	data, err := os.ReadFile(j.file())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return j, nil
+		}
+		return nil, err
+	}
+	for line := range bytes.Lines(data) {
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			break
+		}
+		if line = bytes.TrimSpace(line); len(line) == 0 {
+			continue
+		}
+		if err := apply(line); err != nil {
+			break
+		}
+	}
+	if len(data) > 0 {
+		if err := j.compact(v); err != nil {
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+// record appends rec to the journal and syncs it, compacting v into the
+// snapshot once enough records have built up.
+func (j *journal) record(rec, v any) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	f, err := os.OpenFile(j.file(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening journal: %w", err)
+	}
+	_, err = f.Write(append(data, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	if j.records++; j.records >= journalCompact {
+		return j.compact(v)
+	}
+	return nil
+}
+
+// compact writes v as the snapshot and empties the journal.
+func (j *journal) compact(v any) error {
+	if err := save(j.path, v); err != nil {
+		return err
+	}
+	j.records = 0
+	if err := os.Remove(j.file()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (j *journal) file() string {
+	return j.path + ".journal"
+}
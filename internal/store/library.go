@@ -0,0 +1,206 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+// LibraryEntry is an image file vista knows about outside its download
+// directory.
+type LibraryEntry struct {
+	Path    string    `json:"path"`
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Width   int       `json:"width,omitempty"`
+	Height  int       `json:"height,omitempty"`
+	// ID is the Wallhaven ID when the file name reveals it; URL is filled
+	// in once the ID has been confirmed against the API.
+	ID  string `json:"id,omitempty"`
+	URL string `json:"url,omitempty"`
+}
+
+// Resolution formats the image size like Wallhaven's "1920x1080".
+func (e LibraryEntry) Resolution() string {
+	if e.Width == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", e.Width, e.Height)
+}
+
+// ImportResult counts what Import did with each image it found.
+type ImportResult struct {
+	Added      int
+	Unchanged  int
+	Duplicates []string // files whose content is already in the library
+}
+
+// Library is the index of imported wallpaper folders. It is safe for
+// concurrent use.
+type Library struct {
+	path    string
+	mu      sync.Mutex
+	entries []LibraryEntry
+}
+
+// OpenLibrary loads the library index at path, which needn't exist yet.
+func OpenLibrary(path string) (*Library, error) {
+	l := &Library{path: path}
+	if err := load(path, &l.entries); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// wallhavenName matches the file names Wallhaven serves, e.g.
+// wallhaven-8xkxjo.jpg.
+var wallhavenName = regexp.MustCompile(`^wallhaven-([0-9a-z]+)\.`)
+
+// Import walks dir recursively and indexes every image: its SHA-256,
+// dimensions and, from the file name, its Wallhaven ID. Files already
+// indexed with the same size and modification time aren't rehashed, and
+// files whose content matches an indexed file are reported as duplicates
+// rather than added.
+func (l *Library) Import(dir string) (ImportResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var res ImportResult
+	byPath := make(map[string]int, len(l.entries))
+	byHash := make(map[string]bool, len(l.entries))
+	for i, e := range l.entries {
+		byPath[e.Path] = i
+		byHash[e.SHA256] = true
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !wallpaper.IsImage(p) {
+			return err
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if i, ok := byPath[abs]; ok && l.entries[i].Size == info.Size() && l.entries[i].ModTime.Equal(info.ModTime()) {
+			res.Unchanged++
+			return nil
+		}
+		e, err := indexFile(abs, info)
+		if err != nil {
+			return err
+		}
+		if i, ok := byPath[abs]; ok {
+			l.entries[i] = e // changed on disk
+			byHash[e.SHA256] = true
+			return nil
+		}
+		if byHash[e.SHA256] {
+			res.Duplicates = append(res.Duplicates, abs)
+			return nil
+		}
+		l.entries = append(l.entries, e)
+		byPath[abs] = len(l.entries) - 1
+		byHash[e.SHA256] = true
+		res.Added++
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	return res, save(l.path, l.entries)
+}
+
+// indexFile hashes path and reads its dimensions.
+func indexFile(path string, info fs.FileInfo) (LibraryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return LibraryEntry{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return LibraryEntry{}, fmt.Errorf("hashing %s: %w", path, err)
+	}
+	e := LibraryEntry{
+		Path:    path,
+		SHA256:  hex.EncodeToString(h.Sum(nil)),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if _, err := f.Seek(0, io.SeekStart); err == nil {
+		if cfg, _, err := image.DecodeConfig(f); err == nil {
+			e.Width, e.Height = cfg.Width, cfg.Height
+		}
+	}
+	if m := wallhavenName.FindStringSubmatch(filepath.Base(path)); m != nil {
+		e.ID = m[1]
+	}
+	return e, nil
+}
+
+// Entries returns a copy of the indexed files.
+func (l *Library) Entries() []LibraryEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries)
+}
+
+// Confirm records Wallhaven page URLs, keyed by entry path, for entries
+// whose ID has been looked up, and saves the index.
+func (l *Library) Confirm(urls map[string]string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, e := range l.entries {
+		if u, ok := urls[e.Path]; ok {
+			l.entries[i].URL = u
+		}
+	}
+	return save(l.path, l.entries)
+}
+
+// adopt fills in the ID and page URL of entries that lack them from
+// entries of another library with the same content, and saves the index
+// if anything changed.
+func (l *Library) adopt(other []LibraryEntry) error {
+	byHash := make(map[string]LibraryEntry, len(other))
+	for _, e := range other {
+		byHash[e.SHA256] = e
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	changed := false
+	for i, e := range l.entries {
+		o, ok := byHash[e.SHA256]
+		if !ok {
+			continue
+		}
+		if e.ID == "" && o.ID != "" {
+			l.entries[i].ID = o.ID
+			changed = true
+		}
+		if e.URL == "" && o.URL != "" {
+			l.entries[i].URL = o.URL
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return save(l.path, l.entries)
+}
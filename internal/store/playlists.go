@@ -0,0 +1,88 @@
+package store
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// PlaylistItem is one wallpaper in a saved playlist: a Wallhaven ID, an
+// image URL or a local path.
+type PlaylistItem struct {
+	ID       string `json:"id,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// QueuePlaylist is the playlist the grid's 'a' key appends to.
+const QueuePlaylist = "queue"
+
+// Playlists holds playlists built from the grid. It is safe for concurrent
+// use.
+type Playlists struct {
+	journal *journal
+	mu      sync.Mutex
+	lists   map[string][]PlaylistItem
+}
+
+// playlistRecord is one journaled append. Len is the playlist's length after
+// it, which lets replay skip appends the snapshot already holds.
+type playlistRecord struct {
+	List string       `json:"list"`
+	Item PlaylistItem `json:"item"`
+	Len  int          `json:"len"`
+}
+
+// OpenPlaylists loads the playlists file at path, which needn't exist yet,
+// recovering any appends journaled before a crash.
+func OpenPlaylists(path string) (*Playlists, error) {
+	p := &Playlists{lists: map[string][]PlaylistItem{}}
+	j, err := openJournal(path, &p.lists, p.replay)
+	if err != nil {
+		return nil, err
+	}
+	p.journal = j
+	return p, nil
+}
+
+func (p *Playlists) replay(data json.RawMessage) error {
+	var rec playlistRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+	if len(p.lists[rec.List]) == rec.Len-1 {
+		p.lists[rec.List] = append(p.lists[rec.List], rec.Item)
+	}
+	return nil
+}
+
+// Append adds item to the end of the named playlist, journals the change
+// and returns the playlist's new length.
+func (p *Playlists) Append(name string, item PlaylistItem) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lists[name] = append(p.lists[name], item)
+	n := len(p.lists[name])
+	return n, p.journal.record(playlistRecord{List: name, Item: item, Len: n}, p.lists)
+}
+
+// Get returns a copy of the named playlist.
+func (p *Playlists) Get(name string) ([]PlaylistItem, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	items, ok := p.lists[name]
+	return slices.Clone(items), ok
+}
+
+// Names returns the saved playlist names, sorted.
+func (p *Playlists) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.lists))
+	for name := range p.lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// Portable is the part of the stores that is worth carrying to another
+// machine along with the files of a download directory: the ratings, the
+// favorites, with their tags, source and ID, and the IDs and page URLs the
+// library has confirmed. Paths inside the directory are relative to it, so
+// the other machine can place them under its own.
+//
+// Playlists and the set history stay behind: playlists are named and
+// ordered by hand, so merging two machines' could only guess, and the
+// history records what one desktop showed.
+type Portable struct {
+	Ratings   map[string]Rating
+	Favorites []provider.Wallpaper
+	Library   []LibraryEntry
+}
+
+// Stores bundles the stores a Portable is exported from and merged into.
+type Stores struct {
+	Library   *Library
+	Ratings   *Ratings
+	Favorites *Favorites
+}
+
+// Export returns what of the stores applies to the files under dir.
+// Ratings are keyed by file name and travel whole; local favorites and
+// library entries outside dir are left out, as the other machine won't
+// have their files.
+func (s Stores) Export(dir string) (Portable, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Portable{}, err
+	}
+	p := Portable{Ratings: s.Ratings.All()}
+	for _, wp := range s.Favorites.List() {
+		if !wp.Local() {
+			p.Favorites = append(p.Favorites, wp)
+		} else if wp, ok := relocate(wp, dir, ""); ok {
+			p.Favorites = append(p.Favorites, wp)
+		}
+	}
+	for _, e := range s.Library.Entries() {
+		rel, err := filepath.Rel(dir, e.Path)
+		if err != nil || !filepath.IsLocal(rel) || (e.ID == "" && e.URL == "") {
+			continue
+		}
+		e.Path = filepath.ToSlash(rel)
+		p.Library = append(p.Library, e)
+	}
+	return p, nil
+}
+
+// Merge adds what p knows to the stores, for files copied into dir. The
+// files are indexed into the library first, so ones just received are
+// matched. Local ratings and metadata win: only unrated files get p's
+// rating, and only entries without an ID or page URL get p's, matched by
+// content. Favorites missing here are added after the local ones.
+func (s Stores) Merge(dir string, p Portable) error {
+	if _, err := s.Library.Import(dir); err != nil {
+		return err
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if err := s.Library.adopt(p.Library); err != nil {
+		return err
+	}
+	if err := s.Ratings.adopt(p.Ratings); err != nil {
+		return err
+	}
+	var favorites []provider.Wallpaper
+	for _, wp := range p.Favorites {
+		if !strings.Contains(wp.Path, "://") { // relative to dir
+			var ok bool
+			if wp, ok = relocate(wp, "", dir); !ok {
+				continue
+			}
+		}
+		favorites = append(favorites, wp)
+	}
+	return s.Favorites.adopt(favorites)
+}
+
+// relocate moves wp's local paths from under the directory from to under
+// the directory to; an empty from means they are relative, an empty to
+// makes them so. It reports false when wp's file isn't under from.
+func relocate(wp provider.Wallpaper, from, to string) (provider.Wallpaper, bool) {
+	move := func(p string) (string, bool) {
+		rel := filepath.FromSlash(p)
+		if from != "" {
+			var err error
+			if rel, err = filepath.Rel(from, p); err != nil {
+				return "", false
+			}
+		}
+		if !filepath.IsLocal(rel) {
+			return "", false
+		}
+		if to == "" {
+			return filepath.ToSlash(rel), true
+		}
+		return filepath.Join(to, rel), true
+	}
+	path, ok := move(wp.Path)
+	if !ok {
+		return wp, false
+	}
+	for _, t := range []*string{&wp.Thumbs.Small, &wp.Thumbs.Large, &wp.Thumbs.Original} {
+		if *t == wp.Path {
+			*t = path
+		}
+	}
+	wp.Path = path
+	return wp, true
+}
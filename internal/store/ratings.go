@@ -0,0 +1,134 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// MaxStars is the highest rating.
+const MaxStars = 5
+
+// Rating is the user's verdict on a wallpaper: Stars from 1 to MaxStars,
+// 0 for unrated, and a short note.
+type Rating struct {
+	Stars int    `json:"stars,omitempty"`
+	Note  string `json:"note,omitempty"`
+}
+
+// Ratings holds the stars and notes given to wallpapers, keyed by
+// RatingKey. It is safe for concurrent use.
+type Ratings struct {
+	journal *journal
+	mu      sync.Mutex
+	items   map[string]Rating
+}
+
+// ratingRecord is one journaled change: the key's whole rating, so replay
+// is idempotent. A zero Rating removes the key.
+type ratingRecord struct {
+	Key string `json:"key"`
+	Rating
+}
+
+// OpenRatings loads the ratings file at path, which needn't exist yet,
+// recovering any changes journaled before a crash.
+func OpenRatings(path string) (*Ratings, error) {
+	r := &Ratings{items: map[string]Rating{}}
+	j, err := openJournal(path, &r.items, r.replay)
+	if err != nil {
+		return nil, err
+	}
+	r.journal = j
+	return r, nil
+}
+
+// RatingKey identifies wp for ratings: the name of the file it is, or will
+// be downloaded as, so a remote result and its download share one.
+func RatingKey(wp provider.Wallpaper) string {
+	if wp.Local() {
+		return wallpaper.Key(wp.Path)
+	}
+	return wallpaper.SavedKey(wp.Path, wp.Source, wp.ID)
+}
+
+func (r *Ratings) replay(data json.RawMessage) error {
+	var rec ratingRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+	r.set(rec.Key, rec.Rating)
+	return nil
+}
+
+func (r *Ratings) set(key string, v Rating) {
+	if v == (Rating{}) {
+		delete(r.items, key)
+	} else {
+		r.items[key] = v
+	}
+}
+
+// Get returns the rating of key, zero if it has none.
+func (r *Ratings) Get(key string) Rating {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.items[key]
+}
+
+// Rate gives key stars, keeping its note, and journals the change. Zero
+// stars clears the rating.
+func (r *Ratings) Rate(key string, stars int) error {
+	if stars < 0 || stars > MaxStars {
+		return fmt.Errorf("rating %d is out of range 0-%d", stars, MaxStars)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v := r.items[key]
+	v.Stars = stars
+	return r.update(key, v)
+}
+
+// SetNote replaces key's note, keeping its stars, and journals the change.
+// An empty note removes it.
+func (r *Ratings) SetNote(key, note string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v := r.items[key]
+	v.Note = note
+	return r.update(key, v)
+}
+
+func (r *Ratings) update(key string, v Rating) error {
+	r.set(key, v)
+	return r.journal.record(ratingRecord{Key: key, Rating: v}, r.items)
+}
+
+// All returns a copy of every rating by key.
+func (r *Ratings) All() map[string]Rating {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.items)
+}
+
+// adopt adds the ratings of keys that have none here, saving them in one
+// snapshot rather than a journal record each.
+func (r *Ratings) adopt(other map[string]Rating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for key, v := range other {
+		if _, ok := r.items[key]; !ok && v != (Rating{}) {
+			r.items[key] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.journal.compact(r.items)
+}
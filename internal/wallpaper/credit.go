@@ -0,0 +1,42 @@
+package wallpaper
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Credit is the attribution some sources require wherever their images are
+// used: who made it and where it came from.
+type Credit struct {
+	Author    string `json:"author"`
+	AuthorURL string `json:"author_url,omitempty"`
+	URL       string `json:"url,omitempty"` // the image's page
+	Source    string `json:"source,omitempty"`
+}
+
+// creditPath is the sidecar file holding the credit for an image.
+func creditPath(image string) string {
+	return image + ".credit.json"
+}
+
+// writeCredit stores c beside image.
+func writeCredit(image string, c Credit) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(creditPath(image), append(data, '\n'), 0o644)
+}
+
+// ReadCredit returns the credit recorded beside image when it was set.
+func ReadCredit(image string) (Credit, bool) {
+	data, err := os.ReadFile(creditPath(image))
+	if err != nil {
+		return Credit{}, false
+	}
+	var c Credit
+	if json.Unmarshal(data, &c) != nil || c.Author == "" {
+		return Credit{}, false
+	}
+	return c, true
+}
@@ -0,0 +1,107 @@
+package wallpaper
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// largeImagePixels is the size above which an image is too big to decode
+// casually on a small device: a full bitmap of it takes hundreds of MB.
+const largeImagePixels = 16_000_000
+
+// DecodeReduced decodes the image at path for analysis, such as hashing or
+// a thumbnail, at roughly width pixels across or more. Go's decoders always
+// produce the full bitmap, so large images are first scaled down by
+// vipsthumbnail or ImageMagick when either is installed; both decode JPEGs
+// at a fraction of their size without holding the full image. Without
+// them, or for small images, the file is decoded directly.
+func DecodeReduced(path string, width int) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	if cfg.Width*cfg.Height > largeImagePixels && cfg.Width > width {
+		if img, err := decodeExternal(path, width); err == nil {
+			return img, nil
+		}
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// decodeExternal scales path down to width with the first reducer found
+// and decodes the result.
+func decodeExternal(path string, width int) (image.Image, error) {
+	tmp, err := os.MkdirTemp("", "vista-reduce-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	out := filepath.Join(tmp, "reduced.png")
+	w := strconv.Itoa(width)
+
+	var cmd *exec.Cmd
+	switch {
+	case have("vipsthumbnail"):
+		cmd = exec.Command("vipsthumbnail", path, "--size", w+"x", "-o", out)
+	case have("magick"):
+		cmd = exec.Command("magick", "-define", "jpeg:size="+w+"x"+w, path, "-thumbnail", w+"x", out)
+	case have("convert"):
+		cmd = exec.Command("convert", "-define", "jpeg:size="+w+"x"+w, path, "-thumbnail", w+"x", out)
+	default:
+		return nil, fmt.Errorf("no image reducer installed")
+	}
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", cmd.Args[0], err, msg)
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+func have(tool string) bool {
+	if noExec {
+		return false
+	}
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
+
+// patchedImage is a large image with small regions replaced, used to
+// composite overlays without copying the whole bitmap into a canvas.
+type patchedImage struct {
+	image.Image
+	patches []*image.RGBA
+}
+
+func (p *patchedImage) At(x, y int) color.Color {
+	pt := image.Pt(x, y)
+	for i := len(p.patches) - 1; i >= 0; i-- {
+		if pt.In(p.patches[i].Rect) {
+			return p.patches[i].At(x, y)
+		}
+	}
+	return p.Image.At(x, y)
+}
+
+func (p *patchedImage) ColorModel() color.Model { return color.RGBAModel }
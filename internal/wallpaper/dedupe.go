@@ -0,0 +1,91 @@
+package wallpaper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// hashIndexName is the file in each download directory that records the
+// SHA-256 of every file downloaded there, one "<hex>\t<name>" line per
+// name. Several names share a hash when the same image came from
+// different URLs; only the first is kept on disk.
+const hashIndexName = ".hashes"
+
+// hashIndexMu serialises index updates from concurrent downloads.
+var hashIndexMu sync.Mutex
+
+// hashIndex is a download directory's index, by name and by hash.
+type hashIndex struct {
+	byName map[string]string
+	byHash map[string][]string
+}
+
+func readHashIndex(dir string) hashIndex {
+	idx := hashIndex{byName: map[string]string{}, byHash: map[string][]string{}}
+	f, err := os.Open(filepath.Join(dir, hashIndexName))
+	if err != nil {
+		return idx
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		sum, name, ok := strings.Cut(sc.Text(), "\t")
+		if !ok || idx.byName[name] == sum {
+			continue
+		}
+		idx.byName[name] = sum
+		idx.byHash[sum] = append(idx.byHash[sum], name)
+	}
+	return idx
+}
+
+// file returns the path of an intact file in dir with content sum, or "".
+func (idx hashIndex) file(dir, sum string) string {
+	for _, name := range idx.byHash[sum] {
+		if p := filepath.Join(dir, name); Validate(p) == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// cachedDuplicate returns the file already in dir that an earlier download
+// under name was found to duplicate, or "".
+func cachedDuplicate(dir, name string) string {
+	hashIndexMu.Lock()
+	defer hashIndexMu.Unlock()
+	idx := readHashIndex(dir)
+	sum, ok := idx.byName[name]
+	if !ok {
+		return ""
+	}
+	return idx.file(dir, sum)
+}
+
+// storeDeduplicated moves the downloaded file tmp, whose SHA-256 is sum, to
+// name in dir and records it, unless dir already holds the same content:
+// then tmp is discarded and the existing file's path returned instead.
+func storeDeduplicated(tmp, dir, name, sum string) (string, error) {
+	hashIndexMu.Lock()
+	defer hashIndexMu.Unlock()
+	dest := filepath.Join(dir, name)
+	idx := readHashIndex(dir)
+	if p := idx.file(dir, sum); p != "" && p != dest {
+		dest = p
+	} else if err := os.Rename(tmp, dest); err != nil {
+		return "", fmt.Errorf("saving file: %w", err)
+	}
+	if idx.byName[name] != sum {
+		f, err := os.OpenFile(filepath.Join(dir, hashIndexName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return dest, nil // the index only saves space; the file is fine
+		}
+		fmt.Fprintf(f, "%s\t%s\n", sum, name)
+		f.Close()
+	}
+	return dest, nil
+}
@@ -0,0 +1,57 @@
+package wallpaper
+
+import (
+	"image"
+	"math/bits"
+)
+
+// DHash returns a 64-bit difference hash of the image at path: each bit
+// says whether a cell of a 9×8 grayscale downsample is brighter than its
+// right neighbour. Resized or recompressed copies of an image hash within a
+// few bits of each other; compare with HashDistance.
+func DHash(path string) (uint64, error) {
+	img, err := DecodeReduced(path, 256)
+	if err != nil {
+		return 0, err
+	}
+	return dhash(img), nil
+}
+
+func dhash(img image.Image) uint64 {
+	var gray [8][9]uint64
+	b := img.Bounds()
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 9; x++ {
+			// Average a small grid of samples from the cell rather than every
+			// pixel; full-size wallpapers are millions of pixels.
+			x0, x1 := b.Min.X+x*b.Dx()/9, b.Min.X+(x+1)*b.Dx()/9
+			y0, y1 := b.Min.Y+y*b.Dy()/8, b.Min.Y+(y+1)*b.Dy()/8
+			var sum, n uint64
+			for sy := y0; sy < y1; sy += max((y1-y0)/8, 1) {
+				for sx := x0; sx < x1; sx += max((x1-x0)/8, 1) {
+					r, g, bl, _ := img.At(sx, sy).RGBA()
+					sum += (299*uint64(r) + 587*uint64(g) + 114*uint64(bl)) / 1000
+					n++
+				}
+			}
+			if n > 0 {
+				gray[y][x] = sum / n
+			}
+		}
+	}
+	var h uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			h <<= 1
+			if gray[y][x] > gray[y][x+1] {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+// HashDistance is the number of differing bits between two DHash values.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,46 @@
+package wallpaper
+
+import (
+	"path"
+	"strings"
+)
+
+// FileName returns the name a provider's wallpaper is saved under in the
+// shared download directory. Names that already carry the wallpaper's ID,
+// like Wallhaven's wallhaven-8xkxjo.jpg, are kept so existing downloads
+// stay cached. Any other name gets the provider and ID in front, e.g.
+// unsplash-Ab3x-photo.jpg, so two providers that both serve photo.jpg
+// don't overwrite each other. source is the provider name; a URL there
+// (Wallhaven's own "source" field) is ignored.
+func FileName(rawURL, source, id string) string {
+	base := path.Base(rawURL)
+	if i := strings.IndexAny(base, "?#"); i >= 0 {
+		base = base[:i]
+	}
+	if id == "" || strings.Contains(base, id) {
+		return base
+	}
+	prefix := sanitizeName(id)
+	if s := sanitizeName(strings.ToLower(source)); s != "" && !strings.Contains(source, "/") {
+		prefix = s + "-" + prefix
+	}
+	return prefix + "-" + base
+}
+
+// SavedKey is the Key of a provider's wallpaper as downloaded, under its
+// FileName, so remote results can be compared with the history.
+func SavedKey(rawURL, source, id string) string {
+	return Key(FileName(rawURL, source, id))
+}
+
+// sanitizeName keeps the letters, digits, - and _ of s, so IDs and
+// provider names are safe in a file name.
+func sanitizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,98 @@
+package wallpaper
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParseColor parses "#rgb" or "#rrggbb" (the # is optional).
+func ParseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: use #rrggbb", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: use #rrggbb", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// Generate writes a w×h PNG filled with from, fading vertically to to, into
+// destDir and returns its path. Pass the same colour twice for a solid fill.
+// Files are named after their colours and size, so repeats reuse the image.
+func Generate(from, to color.RGBA, w, h int, destDir string) (string, error) {
+	name := fmt.Sprintf("color-%02x%02x%02x-%02x%02x%02x-%dx%d.png",
+		from.R, from.G, from.B, to.R, to.G, to.B, w, h)
+	dest := filepath.Join(destDir, name)
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating directory: %w", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		c := lerp(from, to, y, h-1)
+		row := img.Pix[y*img.Stride : y*img.Stride+w*4]
+		for x := 0; x < len(row); x += 4 {
+			row[x], row[x+1], row[x+2], row[x+3] = c.R, c.G, c.B, c.A
+		}
+	}
+
+	tmp, err := os.CreateTemp(destDir, ".generate-*")
+	if err != nil {
+		return "", fmt.Errorf("creating file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := png.Encode(tmp, img); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("encoding image: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return "", fmt.Errorf("saving file: %w", err)
+	}
+	return dest, nil
+}
+
+func lerp(a, b color.RGBA, i, n int) color.RGBA {
+	if n <= 0 {
+		return a
+	}
+	mix := func(x, y uint8) uint8 { return uint8((int(x)*(n-i) + int(y)*i) / n) }
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
+
+// ScreenSize returns the resolution of the current screen as reported by
+// xrandr, or 1920×1080 when it can't be determined or SetNoExec is on.
+func ScreenSize() (int, int) {
+	if noExec {
+		return 1920, 1080
+	}
+	out, err := exec.Command("xrandr", "--current").Output()
+	if err == nil {
+		// First line: "Screen 0: minimum 8 x 8, current 2560 x 1440, maximum …"
+		line, _, _ := strings.Cut(string(out), "\n")
+		if _, cur, ok := strings.Cut(line, "current "); ok {
+			var w, h int
+			if _, err := fmt.Sscanf(cur, "%d x %d", &w, &h); err == nil && w > 0 && h > 0 {
+				return w, h
+			}
+		}
+	}
+	return 1920, 1080
+}
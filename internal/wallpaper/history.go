@@ -0,0 +1,201 @@
+package wallpaper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// historyLimit caps the history file; older entries are dropped.
+const historyLimit = 1000
+
+// historySlack is how far past historyLimit the file may grow before it is
+// rewritten, so most changes are a single appended line.
+const historySlack = 100
+
+// appendHistory records path as set now. Each line is
+// "<RFC 3339 time>\t<id>\t<page url>\t<path>", with a trailing "\tundo" when
+// the change was made by Undo; files written before the URL was recorded
+// lack that field.
+//
+// The line is appended and synced, so a crash can at worst leave it
+// unterminated; readLines ignores such a tail and the next append cuts it
+// off. Once the file passes historyLimit+historySlack entries it is
+// rewritten, keeping the newest historyLimit, through a temp file and rename.
+func appendHistory(file string, info Info, p string, undo bool) error {
+	line := fmt.Sprintf("%s\t%s\t%s\t%s", time.Now().Format(time.RFC3339), info.ID, info.URL, p)
+	if undo {
+		line += "\tundo"
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	lines, _ := readLines(file)
+	if len(lines) >= historyLimit+historySlack {
+		lines = append(lines[len(lines)-historyLimit+1:], line)
+		return rewriteHistory(file, lines)
+	}
+
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	end, err := terminatedLength(f)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteAt([]byte(line+"\n"), end); err != nil {
+		return err
+	}
+	if err := f.Truncate(end + int64(len(line)) + 1); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// terminatedLength returns the length of f up to and including its last
+// newline, dropping a partial line left by an interrupted append.
+func terminatedLength(f *os.File) (int64, error) {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	return int64(bytes.LastIndexByte(data, '\n') + 1), nil
+}
+
+// rewriteHistory replaces the history file with lines via a synced temp file
+// and rename.
+func rewriteHistory(file string, lines []string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(strings.Join(lines, "\n") + "\n")
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// RecentKeys returns the keys (see Key) of the last n wallpapers recorded
+// in the history file, most recent last.
+func RecentKeys(file string, n int) []string {
+	lines, _ := readLines(file)
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	keys := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if e, ok := parseHistoryLine(l); ok {
+			keys = append(keys, Key(e.Path))
+		}
+	}
+	return keys
+}
+
+// HistoryEntry is one wallpaper change recorded in the history file.
+type HistoryEntry struct {
+	Time time.Time
+	ID   string
+	URL  string // page the wallpaper came from, if known
+	Path string // local file as set, before overlays
+	Undo bool   // the change undid the one before it
+}
+
+// ReadHistory returns every entry in the history file, oldest first.
+// Malformed lines are skipped.
+func ReadHistory(file string) ([]HistoryEntry, error) {
+	lines, err := readLines(file)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, 0, len(lines))
+	for _, l := range lines {
+		if e, ok := parseHistoryLine(l); ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func parseHistoryLine(l string) (HistoryEntry, bool) {
+	f := strings.Split(l, "\t")
+	if len(f) < 3 {
+		return HistoryEntry{}, false
+	}
+	t, err := time.Parse(time.RFC3339, f[0])
+	if err != nil {
+		return HistoryEntry{}, false
+	}
+	if len(f) == 3 {
+		return HistoryEntry{Time: t, ID: f[1], Path: f[2]}, true
+	}
+	return HistoryEntry{Time: t, ID: f[1], URL: f[2], Path: f[3], Undo: len(f) > 4 && f[4] == "undo"}, true
+}
+
+// Key identifies a wallpaper across remote URLs and local downloads: the
+// file name, which Download preserves. For remote results whose saved name
+// differs from the URL's, use SavedKey.
+func Key(p string) string {
+	return path.Base(filepath.ToSlash(p))
+}
+
+// PickUnrepeated returns a random index into keys whose wallpaper isn't
+// among the last window entries of recent. When the pool is too small to
+// honour the whole window, only the most recent len(keys)-1 are excluded,
+// so it still avoids an immediate repeat. It returns -1 for empty keys.
+func PickUnrepeated(keys, recent []string, window int) int {
+	if len(keys) == 0 {
+		return -1
+	}
+	window = min(window, len(recent), len(keys)-1)
+	for ; window >= 0; window-- {
+		excluded := make(map[string]bool, window)
+		for _, k := range recent[len(recent)-window:] {
+			excluded[k] = true
+		}
+		var pool []int
+		for i, k := range keys {
+			if !excluded[k] {
+				pool = append(pool, i)
+			}
+		}
+		if len(pool) > 0 {
+			return pool[rand.IntN(len(pool))]
+		}
+	}
+	return rand.IntN(len(keys))
+}
+
+// readLines returns the non-blank lines of file. An unterminated last line
+// is the remains of an interrupted append and is skipped.
+func readLines(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for l := range bytes.Lines(data) {
+		if !bytes.HasSuffix(l, []byte("\n")) {
+			break
+		}
+		if l := strings.TrimSpace(string(l)); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
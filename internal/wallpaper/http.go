@@ -0,0 +1,75 @@
+package wallpaper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/retry"
+)
+
+// transport is shared by every download so connections to an image host
+// are kept alive and reused. Go's default keeps only two idle connections
+// per host, so with more download workers than that most thumbnails paid
+// for a fresh TCP and TLS handshake. Hosts that speak HTTP/2 multiplex all
+// requests over one connection instead.
+var transport = func() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 64
+	t.MaxIdleConnsPerHost = 16
+	t.IdleConnTimeout = 90 * time.Second
+	return t
+}()
+
+var httpClient = &http.Client{Transport: transport}
+
+// retries is how often a failed download is repeated; see SetRetries.
+var retries = retry.Default
+
+// SetRetries sets how many times a download that fails transiently, with a
+// network error, 429 or 5xx, is repeated. Zero disables retries.
+func SetRetries(n int) {
+	retries = max(n, 0)
+}
+
+// userAgent identifies vista to image hosts; see SetUserAgent.
+var userAgent = "vista"
+
+// SetUserAgent sets the User-Agent sent with every download. Some hosts
+// reject Go's default one. Call it before the first download.
+func SetUserAgent(ua string) {
+	if ua != "" {
+		userAgent = ua
+	}
+}
+
+// get fetches rawURL with vista's User-Agent, retrying transient
+// failures.
+func get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	return retry.Do(httpClient, req, retries)
+}
+
+// SetConnsPerHost caps the connections open to any one host, e.g. to be
+// gentle with a slow link. Zero, the default, leaves only the download
+// worker count as the limit. Call it before the first download.
+func SetConnsPerHost(n int) {
+	if n <= 0 {
+		return
+	}
+	transport.MaxConnsPerHost = n
+	transport.MaxIdleConnsPerHost = n
+}
+
+// discard drains and closes a response body so its connection can go back
+// to the pool; an unread body forces the connection closed.
+func discard(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, 64<<10)) //nolint:errcheck
+	body.Close()
+}
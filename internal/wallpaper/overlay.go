@@ -0,0 +1,280 @@
+package wallpaper
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Overlay is an element composited onto the wallpaper before it is set.
+type Overlay struct {
+	// Kind is "image" (Path is a logo or other picture), "text" (a random
+	// paragraph from the file at Path) or "calendar" (this month's grid).
+	Kind string
+	Path string
+	// Position is one of top-left, top, top-right, left, center, right,
+	// bottom-left, bottom, bottom-right. Empty means bottom-right.
+	Position string
+	// Opacity from 0 to 1. Zero means fully opaque.
+	Opacity float64
+	// Scale is the pixel size of text and calendar glyphs, or for images the
+	// overlay width as a percentage of the wallpaper width. Zero picks a
+	// size from the wallpaper height.
+	Scale int
+	Color string // text colour, default white
+	// Margin is the distance in pixels from the wallpaper edge.
+	Margin int
+}
+
+// Composite draws overlays onto the image at path and writes the result
+// as a PNG in destDir, returning its path. Text and calendar overlays are
+// regenerated on every call, so each rotation gets a fresh quote and the
+// current date. Earlier composites in destDir are removed. Large images are
+// not copied into a full canvas; only the areas under overlays are.
+func Composite(path string, overlays []Overlay, destDir string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", path, err)
+	}
+	bounds := src.Bounds()
+	var result image.Image
+	var canvas func(r image.Rectangle) draw.Image
+	if bounds.Dx()*bounds.Dy() > largeImagePixels {
+		p := &patchedImage{Image: src}
+		canvas = func(r image.Rectangle) draw.Image {
+			patch := image.NewRGBA(r)
+			draw.Draw(patch, r, p, r.Min, draw.Src)
+			p.patches = append(p.patches, patch)
+			return patch
+		}
+		result = p
+	} else {
+		full := image.NewRGBA(bounds)
+		draw.Draw(full, bounds, src, bounds.Min, draw.Src)
+		canvas = func(image.Rectangle) draw.Image { return full }
+		result = full
+	}
+
+	for _, o := range overlays {
+		layer, err := o.render(bounds)
+		if err != nil {
+			return "", fmt.Errorf("overlay %s: %w", o.Kind, err)
+		}
+		at := o.origin(bounds, layer.Bounds().Size())
+		opacity := o.Opacity
+		if opacity <= 0 || opacity > 1 {
+			opacity = 1
+		}
+		mask := image.NewUniform(color.Alpha{A: uint8(opacity * 0xff)})
+		r := layer.Bounds().Add(at).Intersect(bounds)
+		draw.DrawMask(canvas(r), r, layer, r.Min.Sub(at), mask, image.Point{}, draw.Over)
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating directory: %w", err)
+	}
+	// A new name each time: some desktops ignore a set to the same path.
+	dest := filepath.Join(destDir, fmt.Sprintf("overlay-%d.png", time.Now().UnixNano()))
+	out, err := os.Create(dest)
+	if err != nil {
+		return "", fmt.Errorf("creating file: %w", err)
+	}
+	if err := png.Encode(out, result); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return "", fmt.Errorf("encoding image: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+
+	old, _ := filepath.Glob(filepath.Join(destDir, "overlay-*.png"))
+	for _, p := range old {
+		if p != dest {
+			os.Remove(p)
+		}
+	}
+	return dest, nil
+}
+
+// render draws the overlay onto its own transparent layer.
+func (o Overlay) render(bounds image.Rectangle) (image.Image, error) {
+	scale := o.Scale
+	switch o.Kind {
+	case "image":
+		f, err := os.Open(o.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, err
+		}
+		if scale <= 0 {
+			return img, nil
+		}
+		w := bounds.Dx() * scale / 100
+		h := img.Bounds().Dy() * w / max(img.Bounds().Dx(), 1)
+		return resize(img, w, h), nil
+	case "text":
+		data, err := os.ReadFile(o.Path)
+		if err != nil {
+			return nil, err
+		}
+		if scale <= 0 {
+			scale = max(bounds.Dy()/300, 1)
+		}
+		return o.drawText(wrap(randomParagraph(string(data)), 60), scale), nil
+	case "calendar":
+		if scale <= 0 {
+			scale = max(bounds.Dy()/400, 1)
+		}
+		return o.drawText(calendar(time.Now()), scale), nil
+	}
+	return nil, fmt.Errorf("unknown overlay kind %q: use image, text or calendar", o.Kind)
+}
+
+// origin places an overlay of size sz within bounds according to Position.
+func (o Overlay) origin(bounds image.Rectangle, sz image.Point) image.Point {
+	m := o.Margin
+	x := bounds.Max.X - sz.X - m
+	y := bounds.Max.Y - sz.Y - m
+	pos := o.Position
+	if strings.HasPrefix(pos, "top") {
+		y = bounds.Min.Y + m
+	} else if pos == "left" || pos == "center" || pos == "right" {
+		y = bounds.Min.Y + (bounds.Dy()-sz.Y)/2
+	}
+	if strings.HasSuffix(pos, "left") {
+		x = bounds.Min.X + m
+	} else if pos == "top" || pos == "center" || pos == "bottom" {
+		x = bounds.Min.X + (bounds.Dx()-sz.X)/2
+	}
+	return image.Pt(x, y)
+}
+
+// drawText renders lines in the bitmap font at the given pixel scale, with
+// a one-pixel shadow so it stays legible on light wallpapers.
+func (o Overlay) drawText(lines []string, scale int) image.Image {
+	ink := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if o.Color != "" {
+		if c, err := ParseColor(o.Color); err == nil {
+			ink = c
+		}
+	}
+	shadow := color.RGBA{0, 0, 0, 0x99}
+
+	cols := 0
+	for _, l := range lines {
+		cols = max(cols, len([]rune(l)))
+	}
+	w := (cols*glyphAdvance + 1) * scale
+	h := (len(lines)*lineAdvance + 1) * scale
+	layer := image.NewRGBA(image.Rect(0, 0, w, h))
+	for _, pass := range []struct {
+		c   color.RGBA
+		off int
+	}{{shadow, scale}, {ink, 0}} {
+		for row, l := range lines {
+			for col, r := range []rune(l) {
+				for gy, bits := range glyphRows(r) {
+					for gx, bit := range bits {
+						if bit != '#' {
+							continue
+						}
+						x := (col*glyphAdvance+gx)*scale + pass.off
+						y := (row*lineAdvance+gy)*scale + pass.off
+						draw.Draw(layer, image.Rect(x, y, x+scale, y+scale), image.NewUniform(pass.c), image.Point{}, draw.Over)
+					}
+				}
+			}
+		}
+	}
+	return layer
+}
+
+// randomParagraph picks one blank-line separated paragraph from text.
+func randomParagraph(text string) string {
+	var paras []string
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paras = append(paras, strings.Join(strings.Fields(p), " "))
+		}
+	}
+	if len(paras) == 0 {
+		return ""
+	}
+	return paras[rand.IntN(len(paras))]
+}
+
+// wrap breaks text into lines of at most width runes at word boundaries.
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// calendar lays out the month containing now, Monday first, with today
+// bracketed.
+func calendar(now time.Time) []string {
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	days := first.AddDate(0, 1, -1).Day()
+	lines := []string{
+		now.Format("January 2006"),
+		" Mo  Tu  We  Th  Fr  Sa  Su",
+	}
+	line := strings.Repeat("    ", (int(first.Weekday())+6)%7)
+	for d := 1; d <= days; d++ {
+		if d == now.Day() {
+			line += fmt.Sprintf("[%2d]", d)
+		} else {
+			line += fmt.Sprintf(" %2d ", d)
+		}
+		if len(line) == 28 {
+			lines = append(lines, strings.TrimRight(line, " "))
+			line = ""
+		}
+	}
+	if line != "" {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+// resize scales img to w×h with nearest-neighbour sampling, keeping alpha.
+func resize(img image.Image, w, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, max(w, 1), max(h, 1)))
+	sb := img.Bounds()
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			dst.Set(x, y, img.At(sb.Min.X+x*sb.Dx()/dst.Rect.Dx(), sb.Min.Y+y*sb.Dy()/dst.Rect.Dy()))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,53 @@
+package wallpaper
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultWorkers is the download concurrency used when none is configured.
+const DefaultWorkers = 6
+
+// DownloadAll downloads urls into destDir using at most workers concurrent
+// downloads. The returned slice is parallel to urls; failed or empty URLs
+// leave an empty path. Each path is stored as soon as its download finishes.
+func DownloadAll(urls []string, destDir string, workers int) []string {
+	return DownloadAllContext(context.Background(), urls, destDir, workers)
+}
+
+// DownloadAllContext is DownloadAll with a context. Once it is cancelled no
+// further downloads start and those in flight are abandoned.
+func DownloadAllContext(ctx context.Context, urls []string, destDir string, workers int) []string {
+	if workers < 1 {
+		workers = DefaultWorkers
+	}
+	paths := make([]string, len(urls))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(urls)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if p, err := DownloadContext(ctx, urls[i], destDir, ""); err == nil {
+					paths[i] = p
+				}
+			}
+		}()
+	}
+dispatch:
+	for i, u := range urls {
+		if u == "" {
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return paths
+}
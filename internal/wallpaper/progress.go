@@ -0,0 +1,59 @@
+package wallpaper
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ProgressFunc is told how much of a download has arrived. total is the
+// Content-Length, or -1 when the server didn't send one. It is called at
+// most every progressInterval, and once more when the body is complete,
+// when done equals total; a missing total is then filled in.
+type ProgressFunc func(done, total int64)
+
+const progressInterval = 100 * time.Millisecond
+
+type progressKey struct{}
+
+// WithProgress returns a context under which downloads report their
+// progress to fn. Cached files report nothing.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressBody wraps body to report to the context's ProgressFunc, if any.
+func progressBody(ctx context.Context, body io.Reader, total int64) io.Reader {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	if fn == nil {
+		return body
+	}
+	fn(0, total)
+	return &progressReader{r: body, total: total, fn: fn, last: time.Now()}
+}
+
+type progressReader struct {
+	r         io.Reader
+	done      int64
+	total     int64
+	fn        ProgressFunc
+	last      time.Time
+	finalized bool
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	switch {
+	case err == io.EOF && !p.finalized:
+		p.finalized = true
+		if p.total < 0 {
+			p.total = p.done
+		}
+		p.fn(p.done, p.total)
+	case time.Since(p.last) >= progressInterval:
+		p.last = time.Now()
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,105 @@
+package wallpaper
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// cache is the download directory size cap; see SetCacheLimit.
+var cache struct {
+	sync.Mutex
+	dir         string
+	limit       int64
+	historyFile string
+	keep        func(name string) bool
+}
+
+// SetCacheLimit caps the size of dir, the download directory, at limit
+// bytes: after each download into it, Prune deletes the least recently
+// used images beyond the cap. keep reports images that are never deleted,
+// such as favourites, by file name. Zero limit, the default, disables it.
+func SetCacheLimit(dir string, limit int64, historyFile string, keep func(name string) bool) {
+	cache.Lock()
+	defer cache.Unlock()
+	cache.dir, cache.limit, cache.historyFile, cache.keep = filepath.Clean(dir), limit, historyFile, keep
+}
+
+// pruneCache applies the SetCacheLimit cap after path was downloaded into
+// dir, sparing path itself.
+func pruneCache(dir, path string) {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.limit <= 0 || filepath.Clean(dir) != cache.dir {
+		return
+	}
+	Prune(dir, cache.limit, cache.historyFile, func(name string) bool { //nolint:errcheck
+		return name == filepath.Base(path) || cache.keep != nil && cache.keep(name)
+	})
+}
+
+// Prune deletes images from dir, least recently used first, until the
+// files in it total at most limit bytes, and returns their paths. An image
+// was last used when it was downloaded or, per the history file, last set.
+// Images keep reports by file name are never deleted.
+func Prune(dir string, limit int64, historyFile string, keep func(name string) bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type image struct {
+		path string
+		size int64
+		used time.Time
+	}
+	var (
+		total  int64
+		images []image
+	)
+	last := lastSet(historyFile)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		size := info.Size()
+		if IsImage(e.Name()) {
+			size += sidecarSize(filepath.Join(dir, e.Name()))
+		}
+		total += info.Size()
+		if !IsImage(e.Name()) || keep != nil && keep(e.Name()) {
+			continue
+		}
+		used := info.ModTime()
+		if t := last[e.Name()]; t.After(used) {
+			used = t
+		}
+		images = append(images, image{path: filepath.Join(dir, e.Name()), size: size, used: used})
+	}
+	slices.SortFunc(images, func(a, b image) int { return a.used.Compare(b.used) })
+
+	var removed []string
+	for _, img := range images {
+		if total <= limit {
+			break
+		}
+		if err := os.Remove(img.path); err != nil {
+			continue
+		}
+		os.Remove(creditPath(img.path))
+		total -= img.size
+		removed = append(removed, img.path)
+	}
+	return removed, nil
+}
+
+// sidecarSize is the size of image's credit sidecar, if any, which goes
+// with it.
+func sidecarSize(image string) int64 {
+	if info, err := os.Stat(creditPath(image)); err == nil {
+		return info.Size()
+	}
+	return 0
+}
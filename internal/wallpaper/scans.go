@@ -0,0 +1,137 @@
+package wallpaper
+
+import (
+	"context"
+	"io"
+)
+
+// ScanFunc is told that another scan of a progressive JPEG has arrived.
+// partial is the download so far, holding every complete scan; it is only
+// valid during the call, so copy it to keep it.
+type ScanFunc func(partial string)
+
+type scanKey struct{}
+
+// WithScans returns a context under which downloads of progressive JPEGs
+// report each completed scan to fn, letting a preview sharpen while a large
+// original arrives. Baseline JPEGs, other formats and cached files report
+// nothing.
+func WithScans(ctx context.Context, fn ScanFunc) context.Context {
+	return context.WithValue(ctx, scanKey{}, fn)
+}
+
+// scanWriter returns a writer to tee the download being written to path
+// into, which reports to the context's ScanFunc, or nil when there is none.
+func scanWriter(ctx context.Context, path string) io.Writer {
+	fn, _ := ctx.Value(scanKey{}).(ScanFunc)
+	if fn == nil {
+		return nil
+	}
+	return &jpegScans{path: path, fn: fn}
+}
+
+// jpegScans states.
+const (
+	jpegMarker    = iota // expecting the 0xFF that starts a marker
+	jpegCode             // the marker's code
+	jpegLen1             // high byte of the segment length
+	jpegLen2             // low byte
+	jpegSkip             // segment payload
+	jpegEntropy          // compressed scan data
+	jpegEntropyFF        // 0xFF within scan data: stuffing, a restart or a marker
+	jpegDone             // end of image, or not a JPEG
+)
+
+// jpegScans follows the marker structure of a JPEG as it streams past,
+// skipping segment payloads by their length so markers inside an embedded
+// Exif thumbnail don't count. Each SOS after the first in a progressive
+// (SOF2) image means the scan before it is complete.
+type jpegScans struct {
+	path        string
+	fn          ScanFunc
+	state       int
+	seen        int // bytes so far, to check the SOI marker
+	code        byte
+	length      int
+	progressive bool
+	scans       int
+}
+
+func (s *jpegScans) Write(b []byte) (int, error) {
+	ready := false
+	for _, c := range b {
+		if s.seen < 2 {
+			if c != [2]byte{0xFF, 0xD8}[s.seen] {
+				s.state = jpegDone
+			}
+			s.seen++
+		}
+		switch s.state {
+		case jpegMarker:
+			if c == 0xFF {
+				s.state = jpegCode
+			}
+		case jpegCode:
+			ready = s.marker(c) || ready
+		case jpegLen1:
+			s.length = int(c) << 8
+			s.state = jpegLen2
+		case jpegLen2:
+			s.length = (s.length | int(c)) - 2
+			s.state = jpegSkip
+			if s.length <= 0 {
+				s.segmentDone()
+			}
+		case jpegSkip:
+			if s.length--; s.length == 0 {
+				s.segmentDone()
+			}
+		case jpegEntropy:
+			if c == 0xFF {
+				s.state = jpegEntropyFF
+			}
+		case jpegEntropyFF:
+			switch {
+			case c == 0x00, c >= 0xD0 && c <= 0xD7:
+				s.state = jpegEntropy
+			case c != 0xFF:
+				ready = s.marker(c) || ready
+			}
+		}
+	}
+	if ready {
+		s.fn(s.path)
+	}
+	return len(b), nil
+}
+
+// marker handles a marker code and reports whether it completed a scan.
+func (s *jpegScans) marker(c byte) bool {
+	switch {
+	case c == 0xFF: // fill byte before the code
+		s.state = jpegCode
+	case c == 0xD8, c == 0x01, c >= 0xD0 && c <= 0xD7: // no payload
+		s.state = jpegMarker
+	case c == 0xD9:
+		s.state = jpegDone
+	default:
+		s.code = c
+		s.state = jpegLen1
+		switch c {
+		case 0xC2:
+			s.progressive = true
+		case 0xDA:
+			s.scans++
+			return s.progressive && s.scans > 1
+		}
+	}
+	return false
+}
+
+func (s *jpegScans) segmentDone() {
+	if s.code == 0xDA {
+		s.state = jpegEntropy
+	} else {
+		s.state = jpegMarker
+	}
+}
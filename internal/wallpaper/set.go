@@ -1,21 +1,254 @@
 package wallpaper
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	setwallpaper "github.com/davenicholson-xyz/go-setwallpaper/wallpaper"
 )
 
+// Info describes the wallpaper being set. It fills script placeholders and
+// the VISTA_* environment of the script.
+type Info struct {
+	ID  string
+	URL string
+	// Monitor is the display being set, nil when the wallpaper applies to
+	// every monitor. Nothing sets it yet: vista only sets wallpapers on all
+	// monitors, so scripts see the VISTA_MONITOR* variables empty until
+	// per-monitor setting lands.
+	Monitor *Monitor
+	// Author and AuthorURL credit the creator for sources that require
+	// attribution. When Author is set, Set writes a credit sidecar next to
+	// the image; see ReadCredit.
+	Author    string
+	AuthorURL string
+	Source    string
+}
+
+// Monitor identifies the display a wallpaper is set on, for setters that
+// work per output.
+type Monitor struct {
+	Name  string // output name, e.g. DP-1 or eDP-1
+	Index int    // position among the connected monitors, from 0
+	// X, Y, Width and Height are the monitor's area of the desktop in
+	// pixels.
+	X, Y, Width, Height int
+}
+
+// Geometry formats the area as X11 geometry, e.g. 2560x1440+1920+0.
+func (m Monitor) Geometry() string {
+	return fmt.Sprintf("%dx%d+%d+%d", m.Width, m.Height, m.X, m.Y)
+}
+
+// env returns the monitor as VISTA_MONITOR* variables, all empty when m is
+// nil so scripts can test VISTA_MONITOR to tell the cases apart.
+func (m *Monitor) env() []string {
+	if m == nil {
+		return []string{"VISTA_MONITOR=", "VISTA_MONITOR_INDEX=", "VISTA_MONITOR_GEOMETRY="}
+	}
+	return []string{
+		"VISTA_MONITOR=" + m.Name,
+		"VISTA_MONITOR_INDEX=" + strconv.Itoa(m.Index),
+		"VISTA_MONITOR_GEOMETRY=" + m.Geometry(),
+	}
+}
+
+// name is the monitor's {monitor} placeholder value.
+func (m *Monitor) name() string {
+	if m == nil {
+		return ""
+	}
+	return m.Name
+}
+
+// Script is a user command run in place of the go-setwallpaper library.
+type Script struct {
+	Command string
+	// Shell runs Command through $SHELL -c (or /bin/sh) instead of
+	// splitting it into shell-quoted words and executing it directly.
+	Shell bool
+	// Timeout kills the script if it runs longer. Zero uses
+	// DefaultScriptTimeout.
+	Timeout time.Duration
+	// Log, if set, receives the script's combined stdout and stderr.
+	Log func(format string, args ...any)
+}
+
+// Options controls how Set applies a wallpaper.
+type Options struct {
+	Script     Script
+	Transition Transition
+	// Overlays are composited onto the image first; the result is written
+	// to OverlayDir and that file is set instead.
+	Overlays   []Overlay
+	OverlayDir string
+	// CurrentFile, if set, records the path of each wallpaper Set applies
+	// (before overlays) so it can be restored later; see Current.
+	CurrentFile string
+	// HistoryFile, if set, has every applied wallpaper appended to it; see
+	// ReadHistory and RecentKeys.
+	HistoryFile string
+}
+
+// noExec stops the package running external programs; see SetNoExec.
+var noExec bool
+
+// SetNoExec stops the package running external programs. Scripts are
+// skipped and the go-setwallpaper library sets the wallpaper instead, swww
+// transitions are dropped, large images are decoded in-process and
+// ScreenSize assumes 1920×1080. The library may still call the desktop's
+// own setting tool, as that is the only way to set a wallpaper there.
+func SetNoExec(on bool) {
+	noExec = on
+}
+
+// DefaultScriptTimeout bounds scripts when Script.Timeout is unset.
+const DefaultScriptTimeout = 30 * time.Second
+
 // Set applies the image at path as the desktop wallpaper.
-// If script is non-empty, it is run with path appended as a final argument.
-// Otherwise the go-setwallpaper library is used.
-func Set(path, script string) error {
-	if script != "" {
-		parts := strings.Fields(script)
-		parts = append(parts, path)
-		cmd := exec.Command(parts[0], parts[1:]...)
-		return cmd.Run()
-	}
-	return setwallpaper.Set(path)
+// If o.Script has a command it is run instead of the go-setwallpaper library.
+// Script arguments may contain {path}, {id}, {url} and {monitor}
+// placeholders; without {path} the path is appended as a final argument.
+// The script also receives VISTA_PATH, VISTA_ID, VISTA_URL,
+// VISTA_MONITOR, VISTA_MONITOR_INDEX, VISTA_MONITOR_GEOMETRY and
+// VISTA_TRANSITION_* in its environment. Without a script, a configured
+// transition is played through swww when its daemon is running.
+// The image is validated first so a corrupt file never becomes the wallpaper.
+func Set(path string, info Info, o Options) error {
+	return set(path, info, o, false)
+}
+
+func set(path string, info Info, o Options, undo bool) error {
+	if err := Validate(path); err != nil {
+		return err
+	}
+	original := path
+	if len(o.Overlays) > 0 {
+		composite, err := Composite(path, o.Overlays, o.OverlayDir)
+		if err != nil {
+			return err
+		}
+		path = composite
+	}
+	var err error
+	switch {
+	case o.Script.Command != "" && !noExec:
+		err = runScript(o.Script, path, info, o.Transition)
+	case !o.Transition.IsZero() && swwwAvailable():
+		err = setSwww(path, o.Transition)
+	default:
+		err = setwallpaper.Set(path)
+	}
+	if err == nil && o.CurrentFile != "" {
+		os.MkdirAll(filepath.Dir(o.CurrentFile), 0o755)
+		os.WriteFile(o.CurrentFile, []byte(original+"\n"), 0o644)
+	}
+	if err == nil && info.Author != "" {
+		writeCredit(original, Credit{Author: info.Author, AuthorURL: info.AuthorURL, URL: info.URL, Source: info.Source})
+	}
+	if err == nil && o.HistoryFile != "" {
+		appendHistory(o.HistoryFile, info, original, undo)
+	}
+	return err
+}
+
+// Current returns the wallpaper path last recorded in file by Set, or ""
+// if none is known or it no longer exists.
+func Current(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(data))
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// runScript runs the script with its timeout, logging its output and
+// folding the last line of output into the error on failure.
+func runScript(script Script, path string, info Info, tr Transition) error {
+	timeout := script.Timeout
+	if timeout <= 0 {
+		timeout = DefaultScriptTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd, err := scriptCommand(ctx, script, path, info)
+	if err != nil {
+		return err
+	}
+	cmd.Env = append(cmd.Env, tr.env()...)
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if script.Log != nil && output != "" {
+		script.Log("script %s: %s", cmd.Args[0], output)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("script timed out after %s", timeout)
+	}
+	if err != nil {
+		if i := strings.LastIndexByte(output, '\n'); i >= 0 {
+			output = output[i+1:]
+		}
+		if output != "" {
+			return fmt.Errorf("script: %w: %s", err, output)
+		}
+		return fmt.Errorf("script: %w", err)
+	}
+	return nil
+}
+
+func scriptCommand(ctx context.Context, script Script, path string, info Info) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	if script.Shell {
+		// Placeholders are substituted quoted so odd filenames stay one word.
+		r := strings.NewReplacer("{path}", shellQuote(path), "{id}", shellQuote(info.ID), "{url}", shellQuote(info.URL),
+			"{monitor}", shellQuote(info.Monitor.name()))
+		line := r.Replace(script.Command)
+		if !strings.Contains(script.Command, "{path}") {
+			line += " " + shellQuote(path)
+		}
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		cmd = exec.CommandContext(ctx, shell, "-c", line)
+	} else {
+		parts, err := splitWords(script.Command)
+		if err != nil {
+			return nil, fmt.Errorf("parsing script: %w", err)
+		}
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("parsing script: empty command")
+		}
+		r := strings.NewReplacer("{path}", path, "{id}", info.ID, "{url}", info.URL, "{monitor}", info.Monitor.name())
+		hasPath := false
+		for i, p := range parts {
+			if strings.Contains(p, "{path}") {
+				hasPath = true
+			}
+			parts[i] = r.Replace(p)
+		}
+		if !hasPath {
+			parts = append(parts, path)
+		}
+		cmd = exec.CommandContext(ctx, parts[0], parts[1:]...)
+	}
+
+	cmd.Env = append(os.Environ(),
+		"VISTA_PATH="+path,
+		"VISTA_ID="+info.ID,
+		"VISTA_URL="+info.URL,
+	)
+	cmd.Env = append(cmd.Env, info.Monitor.env()...)
+	return cmd, nil
 }
@@ -0,0 +1,33 @@
+package wallpaper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScriptMonitorEnv(t *testing.T) {
+	const hook = `sh -c 'echo "$VISTA_MONITOR|$VISTA_MONITOR_INDEX|$VISTA_MONITOR_GEOMETRY|{monitor}"'`
+	for _, tt := range []struct {
+		name    string
+		monitor *Monitor
+		want    string
+	}{
+		{"all monitors", nil, "|||"},
+		{"one monitor", &Monitor{Name: "DP-1", Index: 1, X: 1920, Width: 2560, Height: 1440}, "DP-1|1|2560x1440+1920+0|DP-1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := scriptCommand(context.Background(), Script{Command: hook}, "/tmp/wall.jpg", Info{Monitor: tt.monitor})
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(out)); got != tt.want {
+				t.Errorf("hook saw %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
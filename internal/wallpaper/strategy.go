@@ -0,0 +1,184 @@
+package wallpaper
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Candidate is a wallpaper an automatic mode may set.
+type Candidate struct {
+	Key    string   // see Key
+	Rating int      // e.g. Wallhaven favourites; higher is better
+	Stars  int      // the user's own rating, 1-5; 0 if unrated
+	Colors []string // palette as hex, most prominent first
+}
+
+// Strategy chooses which candidate automatic modes (--apply, the daemon)
+// set next. Pick returns an index into cands, or -1 when cands is empty.
+type Strategy interface {
+	Pick(cands []Candidate) int
+}
+
+// Strategies are the names accepted by NewStrategy.
+var Strategies = []string{"random", "least-recent", "top-unseen", "top-rated", "palette"}
+
+// NewStrategy returns the strategy called name, reading the history file
+// for what was set when. window is the repeat window random picks avoid.
+// palette matches against color, a hex colour; it picks at random when
+// color is empty.
+func NewStrategy(name, historyFile string, window int, color string) (Strategy, error) {
+	switch name {
+	case "", "random":
+		return Random{Recent: RecentKeys(historyFile, window), Window: window}, nil
+	case "least-recent":
+		return LeastRecent{LastSet: lastSet(historyFile)}, nil
+	case "top-unseen":
+		return TopUnseen{LastSet: lastSet(historyFile)}, nil
+	case "top-rated":
+		return TopRated{LastSet: lastSet(historyFile)}, nil
+	case "palette":
+		return Palette{Color: color, Fallback: Random{Recent: RecentKeys(historyFile, window), Window: window}}, nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q: use %s", name, strings.Join(Strategies, ", "))
+}
+
+// Random picks uniformly, skipping the last Window wallpapers in Recent;
+// see PickUnrepeated.
+type Random struct {
+	Recent []string
+	Window int
+}
+
+func (s Random) Pick(cands []Candidate) int {
+	keys := make([]string, len(cands))
+	for i, c := range cands {
+		keys[i] = c.Key
+	}
+	return PickUnrepeated(keys, s.Recent, s.Window)
+}
+
+// LeastRecent picks a wallpaper never set before, or else the one set
+// longest ago. Ties are broken at random.
+type LeastRecent struct {
+	LastSet map[string]time.Time
+}
+
+func (s LeastRecent) Pick(cands []Candidate) int {
+	return pickBest(cands, func(a, b Candidate) bool {
+		return s.LastSet[a.Key].Before(s.LastSet[b.Key])
+	})
+}
+
+// TopUnseen picks the highest rated wallpaper never set before, or the
+// highest rated of all when every one has been. Ties are broken at random.
+type TopUnseen struct {
+	LastSet map[string]time.Time
+}
+
+func (s TopUnseen) Pick(cands []Candidate) int {
+	seen := func(c Candidate) bool { _, ok := s.LastSet[c.Key]; return ok }
+	return pickBest(cands, func(a, b Candidate) bool {
+		if seen(a) != seen(b) {
+			return !seen(a)
+		}
+		return a.Rating > b.Rating
+	})
+}
+
+// TopRated picks among the wallpapers the user rated highest the one set
+// longest ago, so the best rotate rather than one repeating. Unrated
+// wallpapers come last. Ties are broken at random.
+type TopRated struct {
+	LastSet map[string]time.Time
+}
+
+func (s TopRated) Pick(cands []Candidate) int {
+	return pickBest(cands, func(a, b Candidate) bool {
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		return s.LastSet[a.Key].Before(s.LastSet[b.Key])
+	})
+}
+
+// Palette picks the wallpaper whose palette comes closest to Color, so
+// changes drift between similar colours instead of jumping. Without a
+// colour, or when no candidate has a palette, it defers to Fallback.
+type Palette struct {
+	Color    string
+	Fallback Strategy
+}
+
+func (s Palette) Pick(cands []Candidate) int {
+	target, ok := parseHex(s.Color)
+	if ok {
+		best, bestDist := -1, -1
+		for i, c := range cands {
+			if d := paletteDistance(target, c.Colors); d >= 0 && (bestDist < 0 || d < bestDist) {
+				best, bestDist = i, d
+			}
+		}
+		if best >= 0 {
+			return best
+		}
+	}
+	return s.Fallback.Pick(cands)
+}
+
+// paletteDistance is the squared RGB distance from target to the nearest
+// colour of palette, or -1 for an empty palette.
+func paletteDistance(target [3]int, palette []string) int {
+	best := -1
+	for _, hex := range palette {
+		c, ok := parseHex(hex)
+		if !ok {
+			continue
+		}
+		dr, dg, db := target[0]-c[0], target[1]-c[1], target[2]-c[2]
+		if d := dr*dr + dg*dg + db*db; best < 0 || d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+func parseHex(s string) ([3]int, bool) {
+	s = strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 6 || err != nil {
+		return [3]int{}, false
+	}
+	return [3]int{int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff)}, true
+}
+
+// pickBest returns a random index among the candidates no other is better
+// than, or -1 for none.
+func pickBest(cands []Candidate, better func(a, b Candidate) bool) int {
+	var best []int
+	for i, c := range cands {
+		switch {
+		case len(best) == 0 || better(c, cands[best[0]]):
+			best = []int{i}
+		case !better(cands[best[0]], c):
+			best = append(best, i)
+		}
+	}
+	if len(best) == 0 {
+		return -1
+	}
+	return best[rand.IntN(len(best))]
+}
+
+// lastSet maps the key of every wallpaper in the history file to when it
+// was last set.
+func lastSet(file string) map[string]time.Time {
+	entries, _ := ReadHistory(file)
+	last := make(map[string]time.Time, len(entries))
+	for _, e := range entries {
+		last[Key(e.Path)] = e.Time
+	}
+	return last
+}
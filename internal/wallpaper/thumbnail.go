@@ -0,0 +1,105 @@
+package wallpaper
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+)
+
+// ThumbWidth matches the width of Wallhaven's small thumbnails, so local
+// grids look and render like remote ones.
+const ThumbWidth = 300
+
+// Thumbnail returns a small JPEG copy of the image at path in cacheDir,
+// creating it on first use. Thumbnails are keyed by path, size and
+// modification time, so an edited or replaced file gets a fresh one.
+func Thumbnail(path, cacheDir string) (string, error) {
+	return Scaled(path, cacheDir, ThumbWidth)
+}
+
+// Scaled is Thumbnail at any width: a JPEG copy of the image at path no
+// wider than width.
+func Scaled(path, cacheDir string, width int) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s\x00%d\x00%d", path, fi.Size(), fi.ModTime().UnixNano())
+	if width != ThumbWidth {
+		key += fmt.Sprintf("\x00%d", width) // existing thumbnails keep their names
+	}
+	sum := sha1.Sum([]byte(key))
+	dest := filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".jpg")
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	img, err := DecodeReduced(path, width)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(cacheDir, "thumb-*")
+	if err != nil {
+		return "", err
+	}
+	err = jpeg.Encode(tmp, shrink(img, width), &jpeg.Options{Quality: 85})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return dest, os.Rename(tmp.Name(), dest)
+}
+
+// Resolution returns the pixel size of the image at path as "WxH", reading
+// only its header.
+func Resolution(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%dx%d", cfg.Width, cfg.Height), nil
+}
+
+// shrink box-filters img down to width w, keeping its aspect ratio. Images
+// already narrower are returned unchanged.
+func shrink(img image.Image, w int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= w {
+		return img
+	}
+	h := max(b.Dy()*w/b.Dx(), 1)
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			var r, g, bl, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, _ := img.At(sx, sy).RGBA()
+					r, g, bl = r+uint64(cr), g+uint64(cg), bl+uint64(cb)
+					n++
+				}
+			}
+			out.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), 0xffff})
+		}
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package wallpaper
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// Transition animates a wallpaper change on backends that support it.
+// The zero value means a hard cut.
+type Transition struct {
+	Type     string  // swww transition type: simple, fade, wipe, grow, outer, random…
+	Duration float64 // seconds; zero uses the backend default
+	Pos      string  // origin for grow/outer, e.g. "center" or "0.8,0.9"
+}
+
+// IsZero reports whether no transition is configured.
+func (t Transition) IsZero() bool {
+	return t.Type == "" && t.Duration == 0 && t.Pos == ""
+}
+
+// env returns the transition as VISTA_TRANSITION_* variables so scripts
+// that drive their own backend can honour it.
+func (t Transition) env() []string {
+	dur := ""
+	if t.Duration > 0 {
+		dur = strconv.FormatFloat(t.Duration, 'f', -1, 64)
+	}
+	return []string{
+		"VISTA_TRANSITION_TYPE=" + t.Type,
+		"VISTA_TRANSITION_DURATION=" + dur,
+		"VISTA_TRANSITION_POS=" + t.Pos,
+	}
+}
+
+// swwwAvailable reports whether swww is installed and its daemon answers.
+func swwwAvailable() bool {
+	if noExec {
+		return false
+	}
+	if _, err := exec.LookPath("swww"); err != nil {
+		return false
+	}
+	return exec.Command("swww", "query").Run() == nil
+}
+
+// setSwww sets path through swww with the transition's options.
+func setSwww(path string, t Transition) error {
+	args := []string{"img", path}
+	if t.Type != "" {
+		args = append(args, "--transition-type", t.Type)
+	}
+	if t.Duration > 0 {
+		args = append(args, "--transition-duration", strconv.FormatFloat(t.Duration, 'f', -1, 64))
+	}
+	if t.Pos != "" {
+		args = append(args, "--transition-pos", t.Pos)
+	}
+	if out, err := exec.Command("swww", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("swww: %w: %s", err, out)
+	}
+	return nil
+}
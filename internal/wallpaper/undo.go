@@ -0,0 +1,47 @@
+package wallpaper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNothingToUndo is returned by Undo when the history holds no earlier
+// wallpaper.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Undo re-applies the wallpaper that was current before the last change
+// recorded in o.HistoryFile. Changes made by Undo are marked in the history,
+// so undoing again keeps stepping back rather than toggling between the
+// last two.
+func Undo(o Options) (HistoryEntry, error) {
+	if o.HistoryFile == "" {
+		return HistoryEntry{}, ErrNothingToUndo
+	}
+	entries, err := ReadHistory(o.HistoryFile)
+	if err != nil && !os.IsNotExist(err) {
+		return HistoryEntry{}, err
+	}
+
+	// Replay the history as a stack: a change pushes, an undo pops back to
+	// the entry below.
+	var stack []HistoryEntry
+	for _, e := range entries {
+		if e.Undo {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+		stack = append(stack, e)
+	}
+	if len(stack) < 2 {
+		return HistoryEntry{}, ErrNothingToUndo
+	}
+
+	prev := stack[len(stack)-2]
+	if _, err := os.Stat(prev.Path); err != nil {
+		return prev, fmt.Errorf("previous wallpaper %s: %w", prev.Path, err)
+	}
+	return prev, set(prev.Path, Info{ID: prev.ID, URL: prev.URL}, o, true)
+}
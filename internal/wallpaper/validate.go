@@ -0,0 +1,60 @@
+package wallpaper
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Validate checks that path is a non-empty image whose header decodes.
+// WebP, which has no registered decoder, only needs a .webp extension and
+// the RIFF/WEBP signature; anything else that doesn't decode, such as an
+// HTML error page saved as .jpg, is rejected.
+func Validate(path string) error {
+	return validateAs(path, path)
+}
+
+// validateAs is Validate for a file whose final name, which decides the
+// extension, is name; for downloads still in a temp file.
+func validateAs(path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return fmt.Errorf("%s: empty file", path)
+	}
+
+	_, _, err = image.DecodeConfig(f)
+	if errors.Is(err, image.ErrFormat) && strings.EqualFold(filepath.Ext(name), ".webp") {
+		var sig [12]byte
+		if _, rerr := f.ReadAt(sig[:], 0); rerr == nil && string(sig[:4]) == "RIFF" && string(sig[8:]) == "WEBP" {
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+var imageExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".webp": true,
+}
+
+// IsImage reports whether name has a wallpaper image extension.
+func IsImage(name string) bool {
+	return imageExts[strings.ToLower(filepath.Ext(name))]
+}
@@ -1,6 +1,9 @@
 package wallpaper
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,9 +12,29 @@ import (
 )
 
 // Download fetches the URL to destDir, returning the local file path.
-// If rawURL is already an absolute local path it is returned as-is.
+// If rawURL is already an absolute local path it is validated and returned
+// as-is. Cached files that fail validation are downloaded again. A download
+// identical to a file already in destDir is not kept; the existing file's
+// path is returned instead, see storeDeduplicated. Downloads into a
+// directory capped with SetCacheLimit may prune older ones.
 func Download(rawURL, destDir string) (string, error) {
+	return DownloadContext(context.Background(), rawURL, destDir, "")
+}
+
+// DownloadAs is Download saving the file as name instead of the URL's base
+// name, see FileName. An empty name uses the base name.
+func DownloadAs(rawURL, destDir, name string) (string, error) {
+	return DownloadContext(context.Background(), rawURL, destDir, name)
+}
+
+// DownloadContext is DownloadAs with a context; cancelling it abandons the
+// request, its retries and the partly written file. See WithProgress for
+// following a download as it arrives.
+func DownloadContext(ctx context.Context, rawURL, destDir, name string) (string, error) {
 	if filepath.IsAbs(rawURL) {
+		if err := Validate(rawURL); err != nil {
+			return "", err
+		}
 		return rawURL, nil
 	}
 
@@ -19,33 +42,59 @@ func Download(rawURL, destDir string) (string, error) {
 		return "", fmt.Errorf("creating download dir: %w", err)
 	}
 
-	filename := filepath.Base(rawURL)
-	dest := filepath.Join(destDir, filename)
+	if name == "" {
+		name = filepath.Base(rawURL)
+	}
+	dest := filepath.Join(destDir, name)
 
-	// skip download if already cached
+	// skip download if already cached and intact
 	if _, err := os.Stat(dest); err == nil {
-		return dest, nil
+		if Validate(dest) == nil {
+			return dest, nil
+		}
+		os.Remove(dest)
+	}
+	if p := cachedDuplicate(destDir, name); p != "" {
+		return p, nil
 	}
 
-	resp, err := http.Get(rawURL) //nolint:gosec
+	resp, err := get(ctx, rawURL)
 	if err != nil {
 		return "", fmt.Errorf("downloading %s: %w", rawURL, err)
 	}
-	defer resp.Body.Close()
+	defer discard(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("download returned status %d", resp.StatusCode)
 	}
 
-	f, err := os.Create(dest)
+	// Write to a temp file and rename, so an interrupted download never
+	// leaves a truncated file that looks cached.
+	f, err := os.CreateTemp(destDir, ".download-*")
 	if err != nil {
 		return "", fmt.Errorf("creating file: %w", err)
 	}
-	defer f.Close()
+	tmp := f.Name()
+	defer os.Remove(tmp)
 
-	if _, err := io.Copy(f, resp.Body); err != nil {
+	h := sha256.New()
+	w := io.MultiWriter(f, h)
+	if sw := scanWriter(ctx, tmp); sw != nil {
+		w = io.MultiWriter(f, h, sw)
+	}
+	if _, err := io.Copy(w, progressBody(ctx, resp.Body, resp.ContentLength)); err != nil {
+		f.Close()
 		return "", fmt.Errorf("writing file: %w", err)
 	}
-
-	return dest, nil
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+	if err := validateAs(tmp, dest); err != nil {
+		return "", fmt.Errorf("downloaded file is not a valid image: %w", err)
+	}
+	path, err := storeDeduplicated(tmp, destDir, name, hex.EncodeToString(h.Sum(nil)))
+	if err == nil {
+		pruneCache(destDir, path)
+	}
+	return path, err
 }
@@ -0,0 +1,127 @@
+// Package provider defines the data model and interface the grid uses to
+// page through wallpapers. Implement Provider to back the grid with a custom
+// data source.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+type Thumbs struct {
+	Large    string `json:"large"`
+	Original string `json:"original"`
+	Small    string `json:"small"`
+}
+
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Wallpaper is a single image offered by a provider. Path is the full
+// resolution image and may be a URL or an absolute local path. Tags is only
+// populated by providers that return tag data. Providers whose licence
+// requires attribution fill Author and AuthorURL, and vista records them
+// alongside the image when it is set. Providers that publish a file hash
+// put it in Checksum and the original is verified against it.
+type Wallpaper struct {
+	ID         string `json:"id"`
+	URL        string `json:"url"`
+	Path       string `json:"path"`
+	Resolution string `json:"resolution"`
+	Thumbs     Thumbs `json:"thumbs"`
+	Tags       []Tag  `json:"tags,omitempty"`
+	Author     string `json:"author,omitempty"`
+	AuthorURL  string `json:"author_url,omitempty"`
+	Source     string `json:"source,omitempty"`   // provider name, e.g. "Unsplash"
+	Checksum   string `json:"checksum,omitempty"` // e.g. "sha256:…", checked before setting
+	// Favorites and Colors come with search results where the provider
+	// has them: a popularity count and the palette as hex, most prominent
+	// first. Automatic selection strategies rank by them.
+	Favorites int      `json:"favorites,omitempty"`
+	Colors    []string `json:"colors,omitempty"`
+}
+
+// Local reports whether w is a file on disk rather than a remote image.
+// Local wallpapers need no network access to show or set, and may have no
+// page URL.
+func (w Wallpaper) Local() bool {
+	return filepath.IsAbs(w.Path)
+}
+
+// Details is the extended metadata of a single wallpaper, as shown by the
+// grid's detail view.
+type Details struct {
+	Wallpaper
+	Uploader  string
+	Views     int
+	Favorites int
+	Colors    []string // hex, e.g. "#1e1e2e"
+	FileSize  int64
+	FileType  string
+	Category  string
+	Purity    string
+	Source    string
+	CreatedAt string
+}
+
+// Detailer is implemented by sources that can look up Details for a
+// wallpaper ID. The grid's 'i' view uses it when one is configured.
+type Detailer interface {
+	GetWallpaper(id string) (Details, error)
+}
+
+// Meta describes a page of results. Seed is set for random sorting and
+// must be sent back with later pages for them to continue the same order.
+type Meta struct {
+	CurrentPage int    `json:"current_page"`
+	LastPage    int    `json:"last_page"`
+	PerPage     int    `json:"per_page"`
+	Total       int    `json:"total"`
+	Seed        string `json:"seed,omitempty"`
+}
+
+// UnmarshalJSON accepts per_page as a number or a numeric string; Wallhaven
+// has sent both.
+func (m *Meta) UnmarshalJSON(b []byte) error {
+	type plain Meta
+	var raw struct {
+		plain
+		PerPage json.Number `json:"per_page"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*m = Meta(raw.plain)
+	if raw.PerPage != "" {
+		n, err := raw.PerPage.Int64()
+		if err != nil {
+			return fmt.Errorf("per_page: %w", err)
+		}
+		m.PerPage = int(n)
+	}
+	return nil
+}
+
+// Provider returns one page of wallpapers at a time. Pages are 1-indexed;
+// Meta.LastPage tells the grid when to stop asking for more.
+type Provider interface {
+	Page(page int) ([]Wallpaper, Meta, error)
+}
+
+// ContextProvider is implemented by providers whose requests can be
+// cancelled. The grid uses PageContext when available and cancels pages
+// still loading when it quits.
+type ContextProvider interface {
+	Provider
+	PageContext(ctx context.Context, page int) ([]Wallpaper, Meta, error)
+}
+
+// ContextDetailer is the cancellable form of Detailer.
+type ContextDetailer interface {
+	Detailer
+	GetWallpaperContext(ctx context.Context, id string) (Details, error)
+}
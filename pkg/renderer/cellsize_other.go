@@ -0,0 +1,7 @@
+//go:build !unix
+
+package renderer
+
+func cellSize() (int, int) {
+	return 0, 0
+}
@@ -0,0 +1,47 @@
+package renderer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const formatCacheFile = "formats.json"
+
+// terminalKey identifies the terminal the detected format applies to.
+func terminalKey() string {
+	key := os.Getenv("TERM") + "|" + os.Getenv("TERM_PROGRAM")
+	if os.Getenv("TMUX") != "" {
+		key += "|tmux"
+	}
+	return key
+}
+
+// cachedFormat returns the chafa format for the current terminal, reading it
+// from dir/formats.json when present and recording a freshly detected one
+// otherwise. An empty dir skips persistence entirely.
+func cachedFormat(dir string) string {
+	if dir == "" {
+		return detectFormat()
+	}
+
+	path := filepath.Join(dir, formatCacheFile)
+	formats := map[string]string{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &formats) //nolint:errcheck // a corrupt cache is just re-detected
+	}
+
+	key := terminalKey()
+	if f, ok := formats[key]; ok && f != "" {
+		return f
+	}
+
+	f := detectFormat()
+	formats[key] = f
+	if data, err := json.MarshalIndent(formats, "", "  "); err == nil {
+		if os.MkdirAll(dir, 0o755) == nil {
+			os.WriteFile(path, data, 0o644) //nolint:errcheck
+		}
+	}
+	return f
+}
@@ -0,0 +1,24 @@
+package renderer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// ITermRenderer draws images with iTerm2's OSC 1337 inline file protocol.
+// iTerm2 decodes JPEG, PNG and GIF itself, so the file is sent as is.
+type ITermRenderer struct{}
+
+func (r *ITermRenderer) Render(imagePath string, width, height int) (string, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", err
+	}
+	// Width and height are in cells; preserveAspectRatio=0 stretches to the
+	// box like chafa --stretch.
+	return fmt.Sprintf("\033]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0;doNotMoveCursor=1:%s\a",
+		len(data), width, height, base64.StdEncoding.EncodeToString(data)), nil
+}
+
+var _ ImageRenderer = (*ITermRenderer)(nil)
@@ -0,0 +1,99 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// kittyChunk is the maximum base64 payload per escape sequence allowed by
+// the kitty graphics protocol.
+const kittyChunk = 4096
+
+// Clearer is implemented by renderers whose images live in a terminal layer
+// that clearing the screen doesn't reliably remove. The grid writes
+// ClearSequence before each full repaint.
+type Clearer interface {
+	ClearSequence() string
+}
+
+// KittyRenderer draws images with the kitty graphics protocol directly,
+// without chafa. It works in kitty, WezTerm, Ghostty and other terminals
+// that implement the protocol.
+//
+// Each image path maps to a stable image ID, so rendering the same path again
+// replaces its placement instead of stacking a new one.
+type KittyRenderer struct{}
+
+func (r *KittyRenderer) Render(imagePath string, width, height int) (string, error) {
+	data, err := pngData(imagePath)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	id := KittyImageID(imagePath)
+	for i := 0; i < len(payload); i += kittyChunk {
+		end := min(i+kittyChunk, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if i == 0 {
+			// a=T transmit+display, f=100 PNG, c/r scale into the cell box,
+			// C=1 leave the cursor alone, q=2 suppress terminal replies.
+			fmt.Fprintf(&b, "\033_Ga=T,f=100,i=%d,p=1,c=%d,r=%d,C=1,q=2,m=%d;%s\033\\",
+				id, width, height, more, payload[i:end])
+		} else {
+			fmt.Fprintf(&b, "\033_Gm=%d;%s\033\\", more, payload[i:end])
+		}
+	}
+	return b.String(), nil
+}
+
+// ClearSequence deletes every image placement on screen.
+func (r *KittyRenderer) ClearSequence() string {
+	return "\033_Ga=d,d=A,q=2\033\\"
+}
+
+// KittyImageID returns the image ID used for path. Callers can delete a
+// single image with "\033_Ga=d,d=I,i=<id>\033\\".
+func KittyImageID(path string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(path))
+	// IDs must be non-zero and fit in 31 bits for some implementations.
+	return h.Sum32()&0x7fffffff | 1
+}
+
+// pngData returns the image at path as PNG bytes, re-encoding other formats.
+func pngData(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(raw, []byte("\x89PNG\r\n\x1a\n")) {
+		return raw, nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", path, err)
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	_ ImageRenderer = (*KittyRenderer)(nil)
+	_ Clearer       = (*KittyRenderer)(nil)
+)
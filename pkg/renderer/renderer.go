@@ -0,0 +1,222 @@
+// Package renderer turns image files into terminal escape sequences.
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ImageRenderer renders an image to a string of terminal escape sequences.
+type ImageRenderer interface {
+	Render(imagePath string, width, height int) (string, error)
+}
+
+// detectFormat picks the best chafa --format value based on environment variables.
+// chafa's --format=auto only inspects $TERM, missing terminals like WezTerm that
+// advertise themselves via $TERM_PROGRAM instead.
+// Inside tmux, pixel protocols (kitty/sixel/iterm) either don't pass through
+// or produce output with embedded newlines that our line-by-line grid drawing
+// corrupts. Force symbols (plain ANSI text) which splits cleanly by line.
+// Outside tmux, check TERM_PROGRAM/TERM to pick the best pixel protocol.
+func detectFormat() string {
+	if os.Getenv("TMUX") != "" {
+		return "symbols"
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "WezTerm":
+		return "kitty"
+	case "iTerm.app":
+		return "iterm"
+	}
+	switch term := os.Getenv("TERM"); {
+	case term == "xterm-kitty":
+		return "kitty"
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"):
+		return "sixels"
+	}
+	if os.Getenv("WT_SESSION") != "" {
+		return "sixels"
+	}
+	return "auto"
+}
+
+// DetectProtocol reports the best graphics protocol for the current terminal:
+// "kitty", "iterm", "sixels", "symbols" (no pixel graphics, e.g. inside tmux) or
+// "auto" when it can't tell.
+func DetectProtocol() string {
+	return detectFormat()
+}
+
+// ChafaRenderer renders images using the chafa CLI tool.
+type ChafaRenderer struct {
+	// CacheDir, if set, persists the detected format per terminal so later
+	// sessions skip detection.
+	CacheDir string
+	// ASCII forces character art drawn only from ASCII symbols.
+	ASCII bool
+
+	probe    sync.Once
+	version  [2]int // major, minor; zero if unknown
+	detected string
+}
+
+// chafaMinPixelVersion is the first chafa release with the kitty and iterm
+// output formats. Older versions reject --format=kitty/iterm outright.
+var chafaMinPixelVersion = [2]int{1, 8}
+
+// format returns the detected format, downgraded to something the installed
+// chafa understands. Detection and the version probe run once per renderer.
+func (r *ChafaRenderer) format() string {
+	r.probe.Do(func() {
+		r.version = chafaVersion()
+		r.detected = cachedFormat(r.CacheDir)
+	})
+	format := r.detected
+	if r.ASCII {
+		return "symbols"
+	}
+	if (format == "kitty" || format == "iterm") && r.version != [2]int{} && versionLess(r.version, chafaMinPixelVersion) {
+		return "symbols"
+	}
+	return format
+}
+
+// chafaVersion parses the major and minor version from `chafa --version`,
+// whose first line looks like "Chafa version 1.8.0".
+func chafaVersion() [2]int {
+	out, err := exec.Command("chafa", "--version").Output()
+	if err != nil {
+		return [2]int{}
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return [2]int{}
+	}
+	var v [2]int
+	if _, err := fmt.Sscanf(fields[len(fields)-1], "%d.%d", &v[0], &v[1]); err != nil {
+		return [2]int{}
+	}
+	return v
+}
+
+func versionLess(a, b [2]int) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+// Render runs chafa with the detected format. If that fails — typically a
+// corrupt or unusual thumbnail tripping up a pixel encoder — it retries once
+// with the symbols format before giving up.
+func (r *ChafaRenderer) Render(imagePath string, width, height int) (string, error) {
+	format := r.format()
+	var extra []string
+	if r.ASCII {
+		extra = append(extra, "--symbols=ascii")
+	}
+	out, err := runChafa(format, imagePath, width, height, extra...)
+	if err != nil && format != "symbols" {
+		var retryErr error
+		out, retryErr = runChafa("symbols", imagePath, width, height, extra...)
+		if retryErr != nil {
+			return "", fmt.Errorf("%w (symbols retry: %v)", err, retryErr)
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	// Chafa emits cursor-hide/show sequences around its output; strip them so
+	// they don't interfere with the cursor state managed by the grid UI.
+	result := strings.ReplaceAll(out, "\033[?25l", "")
+	result = strings.ReplaceAll(result, "\033[?25h", "")
+	return result, nil
+}
+
+func runChafa(format, imagePath string, width, height int, extra ...string) (string, error) {
+	args := []string{
+		"--format=" + format,
+		"--size", fmt.Sprintf("%dx%d", width, height),
+		"--stretch",
+	}
+	args = append(args, extra...)
+	cmd := exec.Command("chafa", append(args, imagePath)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("chafa --format=%s: %w: %s", format, err, msg)
+		}
+		return "", fmt.Errorf("chafa --format=%s: %w", format, err)
+	}
+	return string(out), nil
+}
+
+// IsChafaAvailable checks whether chafa is on PATH.
+func IsChafaAvailable() bool {
+	_, err := exec.LookPath("chafa")
+	return err == nil
+}
+
+// FallbackRenderer renders a simple placeholder when chafa is unavailable.
+type FallbackRenderer struct{}
+
+func (r *FallbackRenderer) Render(imagePath string, width, height int) (string, error) {
+	line := "+" + repeatStr("-", width-2) + "+"
+	mid := "|" + centerStr("NO PREVIEW", width-2) + "|"
+
+	out := line + "\n"
+	for i := 0; i < height-2; i++ {
+		if i == (height-2)/2 {
+			out += mid + "\n"
+		} else {
+			out += "|" + repeatStr(" ", width-2) + "|\n"
+		}
+	}
+	out += line
+	return out, nil
+}
+
+// StubRenderer draws every image as a box filled with "#" and labelled with
+// the file's base name. Its output depends only on the name and size, so a
+// grid drawn with it can be compared against golden frames in tests.
+type StubRenderer struct{}
+
+func (r *StubRenderer) Render(imagePath string, width, height int) (string, error) {
+	name := filepath.Base(imagePath)
+	fill := strings.Repeat("#", max(width, 0))
+	rows := make([]string, max(height, 0))
+	for i := range rows {
+		rows[i] = fill
+	}
+	if len(rows) > 0 {
+		rows[len(rows)/2] = centerStr(name, width)
+	}
+	return strings.Join(rows, "\n"), nil
+}
+
+func repeatStr(s string, n int) string {
+	result := ""
+	for i := 0; i < n; i++ {
+		result += s
+	}
+	return result
+}
+
+func centerStr(s string, width int) string {
+	if len(s) >= width {
+		return s[:width]
+	}
+	pad := (width - len(s)) / 2
+	return repeatStr(" ", pad) + s + repeatStr(" ", width-len(s)-pad)
+}
+
+// ensure the renderers satisfy the interface
+var _ ImageRenderer = (*FallbackRenderer)(nil)
+var _ ImageRenderer = (*StubRenderer)(nil)
+var _ ImageRenderer = (*ChafaRenderer)(nil)
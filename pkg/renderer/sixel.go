@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+)
+
+// Fallback cell size when the terminal doesn't report pixel dimensions.
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+// SixelRenderer draws images as DECSIXEL graphics, decoding and quantising
+// them in Go. It covers xterm (with sixel enabled), foot, mlterm, WezTerm
+// and Windows Terminal without needing chafa.
+type SixelRenderer struct{}
+
+func (r *SixelRenderer) Render(imagePath string, width, height int) (string, error) {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return "", err
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", imagePath, err)
+	}
+
+	cw, ch := cellSize()
+	if cw == 0 || ch == 0 {
+		cw, ch = defaultCellWidth, defaultCellHeight
+	}
+	// Stretch to the cell box like chafa --stretch, then dither onto the
+	// web-safe palette so every image shares one colour table.
+	img := image.NewPaletted(image.Rect(0, 0, width*cw, height*ch), palette.WebSafe)
+	draw.FloydSteinberg.Draw(img, img.Bounds(), scale(src, width*cw, height*ch), image.Point{})
+	return encodeSixel(img), nil
+}
+
+// scale resizes src to w×h with nearest-neighbour sampling; dithering
+// afterwards hides most of the aliasing at thumbnail sizes.
+func scale(src image.Image, w, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	sb := src.Bounds()
+	for y := 0; y < h; y++ {
+		sy := sb.Min.Y + y*sb.Dy()/h
+		for x := 0; x < w; x++ {
+			dst.Set(x, y, src.At(sb.Min.X+x*sb.Dx()/w, sy))
+		}
+	}
+	return dst
+}
+
+// encodeSixel serialises a paletted image as a DCS sixel sequence. Each
+// band of six pixel rows is written once per colour present in it, with
+// runs compressed by the "!n" repeat introducer. No newline follows the
+// last band, so an image on the bottom rows never scrolls the screen.
+func encodeSixel(img *image.Paletted) string {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	var b strings.Builder
+	// P2=1 leaves unset pixels transparent; raster attributes give the size.
+	fmt.Fprintf(&b, "\033P0;1;0q\"1;1;%d;%d", w, h)
+	for i, c := range img.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	row := make([]byte, w)
+	for y0 := 0; y0 < h; y0 += 6 {
+		if y0 > 0 {
+			b.WriteByte('-')
+		}
+		used := make(map[uint8]bool)
+		for y := y0; y < y0+6 && y < h; y++ {
+			for _, idx := range img.Pix[y*img.Stride : y*img.Stride+w] {
+				used[idx] = true
+			}
+		}
+		first := true
+		for idx := range used {
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y0+dy < h; dy++ {
+					if img.Pix[(y0+dy)*img.Stride+x] == idx {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", idx)
+			writeSixelRuns(&b, row)
+		}
+	}
+	b.WriteString("\033\\")
+	return b.String()
+}
+
+func writeSixelRuns(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i + 1
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			b.WriteString(strings.Repeat(string(row[i]), n))
+		}
+		i = j
+	}
+}
+
+var _ ImageRenderer = (*SixelRenderer)(nil)
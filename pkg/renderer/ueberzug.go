@@ -0,0 +1,127 @@
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Placer is implemented by renderers that draw images outside the terminal
+// text stream, such as overlay windows. For these, Render only reserves the
+// cell with blank text and the grid calls Place with the cell's position.
+// Coordinates are 0-based terminal cells.
+type Placer interface {
+	Place(id, imagePath string, col, row, width, height int) error
+	Remove(id string) error
+	Clear() error
+}
+
+// UeberzugRenderer places real X11/Wayland image windows over the terminal
+// via ueberzugpp's JSON layer protocol. It gives pixel-accurate previews in
+// terminals without any graphics protocol (xterm, st, ...).
+type UeberzugRenderer struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	placed map[string]bool
+}
+
+// IsUeberzugAvailable checks whether ueberzugpp is on PATH.
+func IsUeberzugAvailable() bool {
+	_, err := exec.LookPath("ueberzugpp")
+	return err == nil
+}
+
+// Render returns blank lines covering the cell so the grid's text layout is
+// unchanged; the image itself is drawn by Place.
+func (r *UeberzugRenderer) Render(imagePath string, width, height int) (string, error) {
+	line := strings.Repeat(" ", width)
+	return strings.Repeat(line+"\n", height), nil
+}
+
+func (r *UeberzugRenderer) Place(id, imagePath string, col, row, width, height int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.send(map[string]any{
+		"action":     "add",
+		"identifier": id,
+		"x":          col,
+		"y":          row,
+		"max_width":  width,
+		"max_height": height,
+		"path":       imagePath,
+	}); err != nil {
+		return err
+	}
+	r.placed[id] = true
+	return nil
+}
+
+func (r *UeberzugRenderer) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.placed[id] {
+		return nil
+	}
+	delete(r.placed, id)
+	return r.send(map[string]any{"action": "remove", "identifier": id})
+}
+
+// Clear removes every image placed so far.
+func (r *UeberzugRenderer) Clear() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := range r.placed {
+		if err := r.send(map[string]any{"action": "remove", "identifier": id}); err != nil {
+			return err
+		}
+		delete(r.placed, id)
+	}
+	return nil
+}
+
+// Close stops the ueberzugpp process, which also removes its windows.
+func (r *UeberzugRenderer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cmd == nil {
+		return nil
+	}
+	r.stdin.Close()
+	err := r.cmd.Wait()
+	r.cmd = nil
+	return err
+}
+
+// send writes one JSON command, starting ueberzugpp on first use.
+// The caller must hold r.mu.
+func (r *UeberzugRenderer) send(msg map[string]any) error {
+	if r.cmd == nil {
+		cmd := exec.Command("ueberzugpp", "layer", "--silent")
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("ueberzugpp: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("ueberzugpp: %w", err)
+		}
+		r.cmd, r.stdin = cmd, stdin
+		r.placed = make(map[string]bool)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := r.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("ueberzugpp: %w", err)
+	}
+	return nil
+}
+
+var (
+	_ ImageRenderer = (*UeberzugRenderer)(nil)
+	_ Placer        = (*UeberzugRenderer)(nil)
+)
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+)
+
+// detailState is the 'i' detail screen for one wallpaper.
+type detailState struct {
+	wp      provider.Wallpaper
+	info    *provider.Details // nil until the lookup returns
+	err     error
+	preview string // large thumbnail, or the grid thumbnail until it arrives
+	dirty   bool   // needs repainting
+}
+
+// detailResult carries a finished lookup back to the Run loop.
+type detailResult struct {
+	id      string
+	info    provider.Details
+	err     error
+	preview string
+}
+
+// openDetail shows the detail screen for the selection and starts fetching
+// its metadata and a larger thumbnail.
+func (g *Grid) openDetail() {
+	wp := g.wallpapers[g.selected]
+	g.detail = &detailState{wp: wp, preview: g.thumbPaths[g.selected], dirty: true}
+	go func() {
+		res := detailResult{id: wp.ID}
+		if g.detailer == nil {
+			res.err = fmt.Errorf("%s", i18n.T("no metadata source for this list"))
+		} else {
+			res.info, res.err = g.lookupDetails(wp.ID)
+		}
+		if wp.Thumbs.Large != "" {
+			if p, err := wallpaper.DownloadContext(g.ctx, wp.Thumbs.Large, g.tempDir, ""); err == nil {
+				res.preview = p
+			}
+		}
+		g.detailCh <- res
+	}()
+}
+
+// closeDetail returns to the grid.
+func (g *Grid) closeDetail() {
+	g.detail = nil
+	g.prevSelected = -1 // force full redraw
+}
+
+// applyDetail stores a finished lookup if its screen is still open.
+func (g *Grid) applyDetail(res detailResult) {
+	if g.detail == nil || g.detail.wp.ID != res.id {
+		return
+	}
+	if res.err != nil {
+		g.logger.Printf("details %s: %v", res.id, res.err)
+		g.detail.err = res.err
+	} else {
+		g.detail.info = &res.info
+	}
+	if res.preview != "" {
+		g.detail.preview = res.preview
+	}
+	g.detail.dirty = true
+}
+
+// writeDetailTo draws a large preview on the left and the metadata beside
+// it.
+func (g *Grid) writeDetailTo(b *strings.Builder) {
+	d := g.detail
+	w, h := g.termSize()
+	pw := w * 3 / 5
+	ph := min(pw*9/32, h-statusHeight-1)
+
+	g.clearPlacements(b)
+	b.WriteString("\033[H\033[2J")
+	if p, ok := g.renderer.(renderer.Placer); ok && d.preview != "" {
+		p.Place("detail", d.preview, 0, 0, pw, ph) //nolint:errcheck
+	} else {
+		out := g.placeholderLines(pw, ph)
+		if d.preview != "" {
+			if rendered, err := g.renderer.Render(d.preview, pw, ph); err == nil {
+				out = rendered
+			}
+		}
+		for i, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			fmt.Fprintf(b, "\033[%d;1H%s", i+1, line)
+		}
+	}
+
+	col := pw + 3
+	width := w - col
+	for i, line := range g.detailLines(width) {
+		if i >= h-statusHeight {
+			break
+		}
+		fmt.Fprintf(b, "\033[%d;%dH%s", i+1, col, line)
+	}
+}
+
+// detailLines formats the metadata, each line at most width columns wide
+// apart from escape sequences.
+func (g *Grid) detailLines(width int) []string {
+	d := g.detail
+	var lines []string
+	add := func(label, value string) {
+		if value == "" {
+			return
+		}
+		line := fmt.Sprintf("%-11s %s", i18n.T(label), value)
+		if utf8.RuneCountInString(line) > width {
+			line = string([]rune(line)[:max(width-1, 0)]) + "…"
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, selectedColor+d.wp.ID+resetColor, "")
+	add("Resolution", d.wp.Resolution)
+	add("Page", d.wp.URL)
+	if g.ratings != nil {
+		add("Rating", g.ratingLabel(d.wp))
+		add("Note", g.ratings.Get(store.RatingKey(d.wp)).Note)
+	}
+	if d.wp.Local() {
+		add("File", d.wp.Path)
+		if fi, err := os.Stat(d.wp.Path); err == nil {
+			add("Size", humanSize(fi.Size()))
+		}
+	}
+
+	switch {
+	case d.info != nil:
+		info := d.info
+		add("Type", info.FileType)
+		add("Size", humanSize(info.FileSize))
+		add("Category", info.Category)
+		add("Purity", info.Purity)
+		add("Uploader", info.Uploader)
+		add("Uploaded", info.CreatedAt)
+		add("Views", fmt.Sprint(info.Views))
+		add("Favorites", fmt.Sprint(info.Favorites))
+		add("Source", info.Source)
+		if len(info.Colors) > 0 {
+			var sw strings.Builder
+			for _, hex := range info.Colors {
+				if c, err := wallpaper.ParseColor(hex); err == nil {
+					fmt.Fprintf(&sw, "\033[48;2;%d;%d;%dm  %s ", c.R, c.G, c.B, resetColor)
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%-11s %s", i18n.T("Colors"), sw.String()))
+		}
+		if len(info.Tags) > 0 {
+			lines = append(lines, "", i18n.T("Tags"))
+			line := " "
+			for _, t := range info.Tags {
+				if utf8.RuneCountInString(line)+len(t.Name)+1 > width && line != " " {
+					lines = append(lines, line)
+					line = " "
+				}
+				line += " " + t.Name
+			}
+			lines = append(lines, line)
+		}
+	case d.err != nil:
+		lines = append(lines, "", fmt.Sprintf(i18n.T("Details unavailable: %v"), d.err))
+	default:
+		lines = append(lines, "", i18n.T("Loading details..."))
+	}
+	return lines
+}
+
+// humanSize formats a byte count as KB or MB.
+func humanSize(n int64) string {
+	switch {
+	case n <= 0:
+		return ""
+	case n < 1<<20:
+		return fmt.Sprintf("%.0f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
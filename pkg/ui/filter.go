@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// filterState holds the full result set while the grid shows a filtered
+// subset of it.
+type filterState struct {
+	query      string
+	wallpapers []provider.Wallpaper
+	thumbPaths []string
+	rendered   map[int]string
+	selected   int
+}
+
+// matchesFilter reports whether wp matches the filter text against its ID,
+// resolution, local filename and any cached tag names.
+func matchesFilter(wp provider.Wallpaper, query string) bool {
+	q := strings.ToLower(query)
+	if strings.Contains(strings.ToLower(wp.ID), q) ||
+		strings.Contains(wp.Resolution, q) ||
+		(wp.Local() && strings.Contains(strings.ToLower(filepath.Base(wp.Path)), q)) {
+		return true
+	}
+	for _, t := range wp.Tags {
+		if strings.Contains(strings.ToLower(t.Name), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFilter narrows the grid to wallpapers matching query. Applying a new
+// query while a filter is active filters the full set again, not the subset.
+func (g *Grid) applyFilter(query string) {
+	g.clearFilter()
+	if query == "" {
+		return
+	}
+
+	f := &filterState{
+		query:      query,
+		wallpapers: g.wallpapers,
+		thumbPaths: g.thumbPaths,
+		rendered:   g.rendered,
+		selected:   g.selected,
+	}
+
+	var wallpapers []provider.Wallpaper
+	var thumbPaths []string
+	rendered := make(map[int]string)
+	for i, wp := range f.wallpapers {
+		if !matchesFilter(wp, query) {
+			continue
+		}
+		if r, ok := f.rendered[i]; ok {
+			rendered[len(wallpapers)] = r
+		}
+		wallpapers = append(wallpapers, wp)
+		thumbPaths = append(thumbPaths, f.thumbPaths[i])
+	}
+	if len(wallpapers) == 0 {
+		g.status = fmt.Sprintf(i18n.T("no matches for %q"), query)
+		return
+	}
+
+	g.filter = f
+	g.wallpapers = wallpapers
+	g.thumbPaths = thumbPaths
+	g.rendered = rendered
+	g.selected = 0
+	g.scrollRow = 0
+	g.prevSelected = -1
+}
+
+// clearFilter restores the full result set, keeping anything loaded or
+// deleted while the filter was active.
+func (g *Grid) clearFilter() {
+	f := g.filter
+	if f == nil {
+		return
+	}
+	g.filter = nil
+	g.wallpapers = f.wallpapers
+	g.thumbPaths = f.thumbPaths
+	g.rendered = f.rendered
+	g.selected = f.selected
+	if g.selected >= len(g.wallpapers) {
+		g.selected = len(g.wallpapers) - 1
+	}
+	if g.selected < 0 {
+		g.selected = 0
+	}
+	g.scrollRow = 0
+	g.ensureVisible()
+	g.prevSelected = -1
+}
+
+// appendLoaded adds a freshly loaded page, routing it through the filter
+// when one is active so the full set stays complete.
+func (g *Grid) appendLoaded(wallpapers []provider.Wallpaper, thumbPaths []string) {
+	if g.filter == nil {
+		g.wallpapers = append(g.wallpapers, wallpapers...)
+		g.thumbPaths = append(g.thumbPaths, thumbPaths...)
+		return
+	}
+	g.filter.wallpapers = append(g.filter.wallpapers, wallpapers...)
+	g.filter.thumbPaths = append(g.filter.thumbPaths, thumbPaths...)
+	for i, wp := range wallpapers {
+		if matchesFilter(wp, g.filter.query) {
+			g.wallpapers = append(g.wallpapers, wp)
+			g.thumbPaths = append(g.thumbPaths, thumbPaths[i])
+		}
+	}
+}
+
+// forgetFiltered drops a deleted wallpaper from the full set as well, so
+// clearing the filter doesn't bring it back.
+func (g *Grid) forgetFiltered(path string) {
+	f := g.filter
+	if f == nil {
+		return
+	}
+	for i, wp := range f.wallpapers {
+		if wp.Path != path {
+			continue
+		}
+		f.wallpapers = append(f.wallpapers[:i], f.wallpapers[i+1:]...)
+		f.thumbPaths = append(f.thumbPaths[:i], f.thumbPaths[i+1:]...)
+		// The full-set render cache is keyed by index; drop it rather than
+		// re-key, it is cheap to rebuild.
+		f.rendered = make(map[int]string)
+		if f.selected >= i && f.selected > 0 {
+			f.selected--
+		}
+		return
+	}
+}
+
+// handlePromptKey edits the filter or note prompt. Enter applies, Esc
+// cancels.
+func (g *Grid) handlePromptKey(key []byte) {
+	if len(key) != 1 {
+		return
+	}
+	switch c := key[0]; {
+	case c == '\r' || c == '\n':
+		g.prompting = false
+		if g.noting {
+			g.noting = false
+			g.noteSelected(g.promptText)
+			break
+		}
+		g.applyFilter(g.promptText)
+	case c == 27 || c == 3: // Esc or Ctrl+C
+		g.prompting = false
+		g.noting = false
+	case c == 127 || c == 8: // Backspace
+		if n := len(g.promptText); n > 0 {
+			g.promptText = g.promptText[:n-1]
+		}
+	case c >= ' ' && c < 127:
+		g.promptText += string(c)
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// firstScreenFile holds the saved page in an Options.FirstScreen directory,
+// next to copies of its thumbnails.
+const firstScreenFile = "first-screen.json"
+
+// FirstScreen is the first page of a search as saved for the next launch.
+// Its wallpapers' thumbnails point at local copies, so the grid can show
+// them without the network.
+type FirstScreen struct {
+	Key        string               `json:"key"`
+	Meta       provider.Meta        `json:"meta"`
+	Wallpapers []provider.Wallpaper `json:"wallpapers"`
+}
+
+// LoadFirstScreen returns the page saved in dir if it was saved for the
+// search identified by key.
+func LoadFirstScreen(dir, key string) (FirstScreen, bool) {
+	var fs FirstScreen
+	b, err := os.ReadFile(filepath.Join(dir, firstScreenFile))
+	if err != nil || json.Unmarshal(b, &fs) != nil || fs.Key != key || len(fs.Wallpapers) == 0 {
+		return FirstScreen{}, false
+	}
+	return fs, true
+}
+
+// Refresh fetches the first page of p in the background and replaces the
+// results with it when it arrives, keeping the selection. Call it before
+// Run when the grid opens on a saved FirstScreen.
+func (g *Grid) Refresh(p provider.Provider) {
+	q := Query{}
+	if g.query != nil {
+		q = *g.query
+	}
+	g.startSearch(q, p, "", i18n.T("No results found."), true)
+}
+
+// saveFirstScreen saves wallpapers and copies of their thumbnails to the
+// Options.FirstScreen directory, replacing the last saved page. Failures
+// are only logged; the cache is an optimisation.
+func (g *Grid) saveFirstScreen(meta provider.Meta, wallpapers []provider.Wallpaper, thumbPaths []string) {
+	if g.firstScreen == "" {
+		return
+	}
+	if err := saveFirstScreen(g.firstScreen, g.firstScreenKey, meta, wallpapers, thumbPaths); err != nil {
+		g.logger.Printf("save first screen: %v", err)
+	}
+}
+
+func saveFirstScreen(dir, key string, meta provider.Meta, wallpapers []provider.Wallpaper, thumbPaths []string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	fs := FirstScreen{Key: key, Meta: meta}
+	for i, wp := range wallpapers {
+		if i >= len(thumbPaths) || thumbPaths[i] == "" {
+			continue // a screen with gaps would be refetched anyway
+		}
+		thumb := filepath.Join(dir, fmt.Sprintf("%d-%s", i, filepath.Base(thumbPaths[i])))
+		if err := copyFile(thumbPaths[i], thumb); err != nil {
+			return err
+		}
+		wp.Thumbs = provider.Thumbs{Small: thumb, Large: thumb, Original: thumb}
+		fs.Wallpapers = append(fs.Wallpapers, wp)
+	}
+	b, err := json.Marshal(fs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, firstScreenFile), b, 0o644)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Frame lays the grid out for the current size and returns a full repaint
+// exactly as it would be written to the terminal. With Options.Size and
+// renderer.StubRenderer the output is deterministic, so tests can compare
+// it, or its Screen, against a golden copy.
+func (g *Grid) Frame() string {
+	g.layout()
+	g.ensureVisible()
+	g.prevSelected = -1
+	return g.render()
+}
+
+// Diff returns what the grid would write to bring the screen up to date
+// since the last Frame or Diff, such as only the two cells whose selection
+// changed.
+func (g *Grid) Diff() string {
+	return g.render()
+}
+
+// Select moves the selection to index i, scrolling it into view, as the
+// movement keys do. Out of range indexes are ignored.
+func (g *Grid) Select(i int) {
+	g.selectIndex(i)
+}
+
+// Screen plays terminal output such as a Frame onto a blank width×height
+// screen and returns its rows as plain text, trailing spaces trimmed.
+// Cursor positioning and screen and line clears are applied; colours and
+// other escape sequences, including image protocols, are dropped.
+func Screen(out string, width, height int) []string {
+	screen := make([][]rune, height)
+	clear := func(r int) { screen[r] = []rune(strings.Repeat(" ", width)) }
+	for r := range screen {
+		clear(r)
+	}
+	row, col := 0, 0
+	for i := 0; i < len(out); {
+		switch c := out[i]; {
+		case c == 0x1b && i+1 < len(out) && out[i+1] == '[':
+			j := i + 2
+			for j < len(out) && (out[j] < 0x40 || out[j] > 0x7e) {
+				j++
+			}
+			if j == len(out) {
+				i = j
+				continue
+			}
+			params := strings.Split(out[i+2:j], ";")
+			switch out[j] {
+			case 'H':
+				row, col = csiParam(params, 0)-1, csiParam(params, 1)-1
+			case 'J':
+				if params[0] == "2" {
+					for r := range screen {
+						clear(r)
+					}
+				}
+			case 'K':
+				if params[0] == "2" && row >= 0 && row < height {
+					clear(row)
+				}
+			}
+			i = j + 1
+		case c == 0x1b && i+1 < len(out) && strings.IndexByte("P]_^X", out[i+1]) >= 0:
+			// DCS, OSC, APC and the like (sixel, kitty, iTerm images) run
+			// to a string terminator or, for OSC, a bell.
+			j := i + 2
+			for j < len(out) && out[j] != 0x07 && !(out[j] == 0x1b && j+1 < len(out) && out[j+1] == '\\') {
+				j++
+			}
+			if j < len(out) && out[j] == 0x1b {
+				j++
+			}
+			i = j + 1
+		case c == 0x1b:
+			i += 2
+		case c == '\n':
+			row, col = row+1, 0
+			i++
+		case c == '\r':
+			col = 0
+			i++
+		case c < 0x20:
+			i++
+		default:
+			r, n := utf8.DecodeRuneInString(out[i:])
+			if row >= 0 && row < height && col >= 0 && col < width {
+				screen[row][col] = r
+			}
+			col++
+			i += n
+		}
+	}
+	lines := make([]string, height)
+	for r, line := range screen {
+		lines[r] = strings.TrimRight(string(line), " ")
+	}
+	return lines
+}
+
+// csiParam returns parameter i of a cursor sequence, defaulting to 1.
+func csiParam(params []string, i int) int {
+	if i < len(params) {
+		if n, err := strconv.Atoi(params[i]); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 1
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+)
+
+const frameW, frameH = 60, 20
+
+// frameGrid returns a 60×20 ASCII grid of n local wallpapers drawn by the
+// stub renderer, with their thumbnails in place.
+func frameGrid(t *testing.T, n int) *Grid {
+	t.Helper()
+	dir := t.TempDir()
+	var wps []provider.Wallpaper
+	for i := range n {
+		p := filepath.Join(dir, fmt.Sprintf("wp%d.png", i+1))
+		f, err := os.Create(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 16, 9))); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		wps = append(wps, provider.Wallpaper{ID: fmt.Sprint(i + 1), Path: p, Resolution: "1920x1080", Thumbs: provider.Thumbs{Small: p}})
+	}
+	g := NewGrid(wps, &renderer.StubRenderer{}, nil, 1, Options{
+		Output: io.Discard,
+		Size:   func() (int, int) { return frameW, frameH },
+		ASCII:  true,
+	})
+	t.Cleanup(g.Cleanup)
+	g.prefetchThumbs()
+	return g
+}
+
+// golden pads want to a full screen of rows.
+func golden(want ...string) []string {
+	return append(want, make([]string, frameH-len(want))...)
+}
+
+func checkScreen(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if !slices.Equal(got, want) {
+		t.Errorf("%s:\ngot\n%s\nwant\n%s", what, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFrameLayout(t *testing.T) {
+	g := frameGrid(t, 6)
+	checkScreen(t, "frame", Screen(g.Frame(), frameW, frameH), golden(
+		"+------------------+########################################",
+		"############################################################",
+		"      wp1.png             wp2.png             wp3.png",
+		"############################################################",
+		"############################################################",
+		"+-   1920x1080    -+     1920x1080           1920x1080",
+		"############################################################",
+		"############################################################",
+		"      wp4.png             wp5.png             wp6.png",
+		"############################################################",
+		"############################################################",
+		"     1920x1080           1920x1080           1920x1080",
+	))
+}
+
+func TestSelectDiff(t *testing.T) {
+	g := frameGrid(t, 6)
+	before := g.Frame()
+	g.Select(1)
+	diff := g.Diff()
+
+	// Only the old and new selection are repainted.
+	checkScreen(t, "diff", Screen(diff, frameW, frameH), golden(
+		"####################+------------------+",
+		"########################################",
+		"      wp1.png             wp2.png",
+		"########################################",
+		"########################################",
+		"     1920x1080      +-   1920x1080    -+",
+	))
+	// Applied to the old frame, it gives the same screen as a new one.
+	checkScreen(t, "frame and diff", Screen(before+diff, frameW, frameH), Screen(g.Frame(), frameW, frameH))
+
+	if again := g.Diff(); Screen(again, frameW, frameH)[0] != "" {
+		t.Errorf("second diff repainted cells: %q", again)
+	}
+}
@@ -0,0 +1,1877 @@
+// Package ui implements the interactive terminal image grid. It can be
+// embedded by other tools by supplying a provider.Provider and a
+// renderer.ImageRenderer.
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+	"golang.org/x/term"
+)
+
+const (
+	minCellWidth  = 20 // terminal columns
+	minCellHeight = 5  // terminal rows (image portion)
+	// portraitMinCellWidth is minCellWidth for portrait cells, which are
+	// too short to recognise anything at 20 columns.
+	portraitMinCellWidth = 28
+	labelHeight   = 1  // rows for resolution label
+	statusHeight  = 1  // rows reserved at the bottom for the status line
+)
+
+// Options configures optional Grid behaviour.
+type Options struct {
+	DownloadDir string
+	Script      string
+	// ScriptShell runs Script through the user's shell rather than
+	// splitting it into quoted words.
+	ScriptShell bool
+	// ScriptTimeout bounds how long Script may run. Zero uses a default.
+	ScriptTimeout time.Duration
+	// Transition animates wallpaper changes where the backend supports it.
+	Transition wallpaper.Transition
+	// Overlays are composited onto each wallpaper before it is set, into
+	// OverlayDir.
+	Overlays   []wallpaper.Overlay
+	OverlayDir string
+	// CurrentFile records the last wallpaper set so 't' can revert to it.
+	CurrentFile string
+	// HistoryFile logs every wallpaper set; '*' avoids the last
+	// RepeatWindow of them.
+	HistoryFile  string
+	RepeatWindow int
+	// MinRating makes --apply and daemon picks skip wallpapers rated below
+	// it in Ratings. Unrated ones are kept. The grid ignores it.
+	MinRating int
+	// Favorites backs the 'f' key and the star in cell labels. Nil
+	// disables both.
+	Favorites *store.Favorites
+	// Playlists receives wallpapers queued with 'a'. Nil disables the key.
+	Playlists *store.Playlists
+	// Ratings backs the 'r' and 'n' keys and the stars in cell labels. Nil
+	// disables them.
+	Ratings *store.Ratings
+	// Detailer supplies the metadata for the 'i' detail view and the tags
+	// of the 'c' tag cloud.
+	Detailer provider.Detailer
+	// Meta is the first page's search metadata. When it has a total, the
+	// status bar shows how much is loaded, the page and the random seed.
+	Meta provider.Meta
+	// Search opens a new result set for a query, such as a tag picked in
+	// the tag cloud. Nil disables searching from the grid.
+	Search func(q Query) provider.Provider
+	// Query is the search the initial wallpapers came from, letting the
+	// filter keys re-run it. Nil for fixed lists such as favorites.
+	Query *Query
+	// FirstScreen, when set, is a directory where the first page of the
+	// initial results and their thumbnails are saved, for a later launch
+	// of the same search to show at once; see LoadFirstScreen and Refresh.
+	// FirstScreenKey identifies that search.
+	FirstScreen    string
+	FirstScreenKey string
+	// PurityLocked disables the purity keys.
+	PurityLocked bool
+	Verbose  bool
+
+	// ConfirmQuit asks for confirmation before quitting while wallpaper
+	// downloads started with 's' are still in flight.
+	ConfirmQuit bool
+	// IdleTimeout exits the grid after this long without input. Zero disables.
+	IdleTimeout time.Duration
+	// StayOpen makes Enter set the wallpaper in the background like 's'
+	// instead of exiting the grid.
+	StayOpen bool
+	// BrowseOnly disables every key that changes the desktop or deletes
+	// files (set, try, undo, delete) and hides them from the help overlay.
+	// Enter opens the detail screen instead.
+	BrowseOnly bool
+	// NoExec stops the grid starting a browser for 'o'; the URL is shown
+	// in the status line instead.
+	NoExec bool
+	// OpenDetail starts Run on the detail screen of the first wallpaper,
+	// for a single wallpaper looked up by ID.
+	OpenDetail bool
+	// Review turns the grid into a cleanup pass over local files: space
+	// keeps the selection and d deletes it, each moving on to the next
+	// undecided wallpaper, and labels show file sizes. Grid.Review returns
+	// the tally once Run ends.
+	Review bool
+	// TryDuration is how long 't' keeps a wallpaper before reverting.
+	// Zero uses a 10 second default.
+	TryDuration time.Duration
+	// SlideshowInterval is how long the 'p' fullscreen slideshow shows each
+	// wallpaper. Zero uses a 5 second default.
+	SlideshowInterval time.Duration
+	// ThumbSize picks which provider thumbnail the grid renders: "small"
+	// (default), "large" or "original". Larger thumbs look sharper under
+	// pixel protocols at the cost of bandwidth.
+	ThumbSize string
+	// EnlargeSelected draws unselected thumbnails inset by a one character
+	// margin so the selected one stands out at full cell size.
+	EnlargeSelected bool
+	// CellAspect is the shape of a cell's image area as width over height,
+	// e.g. 16.0/9. Zero picks 16:9, or 9:16 on a terminal taller than it is
+	// wide, such as one on a portrait monitor.
+	CellAspect float64
+	// Gap is the number of blank columns between cells; rows get half as
+	// many blank lines since terminal cells are roughly twice as tall.
+	Gap int
+	// BorderStyle picks the frame characters: double (default), single,
+	// rounded, heavy or ascii.
+	BorderStyle string
+	// BorderUnselected draws a dim frame around unselected cells too.
+	BorderUnselected bool
+	// Selection is how the selected cell is marked beyond its colour, for
+	// colour-blind users and monochrome terminals: color (default), inverse,
+	// thick or arrow.
+	Selection string
+	// ASCII replaces every box-drawing and block character in the UI with
+	// plain ASCII, overriding BorderStyle.
+	ASCII bool
+	// DownloadWorkers bounds concurrent thumbnail downloads. Zero uses a
+	// sensible default.
+	DownloadWorkers int
+	// PrefetchPages keeps at least that many pages of results and
+	// thumbnails loaded beyond the selection. Scrolling faster raises it, up
+	// to MaxPrefetchPages (4 if zero), so quick flicks don't reach the end of
+	// what is loaded; see lookahead.
+	PrefetchPages    int
+	MaxPrefetchPages int
+	// Logger receives diagnostics that can't be printed while the terminal
+	// is in raw mode, such as render failures. Nil discards them.
+	Logger *log.Logger
+	// Record receives the session as an asciinema cast: everything drawn
+	// and every key pressed. Nil records nothing.
+	Record io.Writer
+	// Output is where the grid draws. Nil uses stdout.
+	Output io.Writer
+	// Size reports the terminal size in columns and rows. Nil asks stdout's
+	// terminal. Together with Output and renderer.StubRenderer it makes
+	// drawing deterministic; see Frame.
+	Size func() (width, height int)
+}
+
+type loadResult struct {
+	gen        int // results generation the page belongs to
+	page       int
+	meta       provider.Meta
+	wallpapers []provider.Wallpaper
+	thumbPaths []string
+	nextPage   int
+	err        error // the page failed even after retries and was skipped
+}
+
+// Grid manages the interactive wallpaper grid.
+type Grid struct {
+	wallpapers  []provider.Wallpaper
+	renderer    renderer.ImageRenderer
+	downloadDir string
+	setOpts     wallpaper.Options
+	tempDir     string
+	// ctx is cancelled when Run returns, abandoning background requests.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	cols      int
+	cellW     int
+	cellH     int
+	selected  int
+	scrollRow int // first visible grid row (0-indexed)
+
+	// cached rendered images: index -> rendered string
+	rendered   map[int]string
+	thumbPaths []string
+	thumbCh    chan thumbResult // thumbnails that succeeded on a retry
+	retrySem   chan struct{}
+	refreshed  []int // cells whose thumbnail arrived since the last draw
+
+	// draw state — track what was last rendered to enable selective updates
+	prevSelected  int
+	prevScrollRow int
+	prevCount     int
+
+	showHelp bool
+	verbose  bool
+
+	confirmQuit bool
+	quitPending bool // waiting for y/n after a quit with downloads in flight
+	idleTimeout time.Duration
+	stayOpen    bool
+	browseOnly  bool
+	noExec      bool
+	review      *reviewState // nil unless Options.Review
+	inflight    int32       // background wallpaper downloads, updated atomically
+	statusCh    chan string // status messages from background work
+
+	repeatWindow int // recent sets '*' won't jump to
+	favorites    *store.Favorites
+	playlists    *store.Playlists
+	ratings      *store.Ratings
+
+	detailer provider.Detailer
+	detail   *detailState // 'i' detail screen, nil when closed
+	detailCh chan detailResult
+	autoInfo bool // open the detail screen when Run starts; Options.OpenDetail
+
+	search       func(q Query) provider.Provider
+	query        *Query // of the current results; nil for a fixed list
+	searchGen    int
+	searchCh     chan searchResult
+	// firstScreen and firstScreenKey are Options.FirstScreen and
+	// Options.FirstScreenKey.
+	firstScreen, firstScreenKey string
+	purityLocked bool
+
+	cloud    *tagCloudState // 'c' tag cloud, nil when closed
+	cloudGen int
+	tagCache map[string][]provider.Tag // tags looked up for the cloud, by ID
+	tagCh    chan tagResult
+
+	marks      map[byte]string // '1'-'9' -> wallpaper ID, set with m<n>
+	markPrefix byte            // 'm', '\'' or 'r' while waiting for the digit
+	goPending  bool            // 'g' pressed, waiting for the second of gg
+
+	trying      *tryState // wallpaper on trial with 't', nil otherwise
+	tryDuration time.Duration
+	tryCh       chan tryState
+
+	slideshow     bool
+	slideInterval time.Duration
+	streamed      streamed // sharpest copy yet of a wallpaper being set
+	streamCh      chan streamed
+	thumbSize     string
+	logger        *log.Logger
+	workers       int
+	prefetch      int               // Options.PrefetchPages
+	maxPrefetch   int               // Options.MaxPrefetchPages
+	scroll        scrollSpeed
+	recordTo      io.Writer
+	out           io.Writer         // Options.Output; nil for stdout
+	size          func() (int, int) // Options.Size; nil asks the terminal
+	rec           *recorder         // nil unless recording
+
+	enlargeSelected  bool
+	selRendered      map[string]string // full-size renders keyed by thumb path
+	gapX, gapY       int
+	cellAspect       float64 // Options.CellAspect; 0 picks from the terminal shape
+	border           borderStyle
+	selBorder        borderStyle // frame of the selected cell
+	selection        string      // Options.Selection
+	fill             fillGlyphs
+	borderUnselected bool
+
+	// status line — only repainted when the text changes
+	status     string
+	prevStatus string
+
+	// Ctrl-f filter prompt and the full result set while a filter is active
+	prompting  bool
+	noting     bool // the prompt edits the selection's note, not the filter
+	promptText string
+	filter     *filterState
+
+	// pagination / async loading; gen changes when a search replaces the
+	// results so pages of the old ones are dropped
+	provider   provider.Provider
+	gen        int
+	meta       provider.Meta      // of the last page loaded
+	nextPage   int                // next page to append
+	requested  int                // next page to fetch; those between are in flight
+	pending    map[int]loadResult // pages that arrived before an earlier one
+	lastPage   int
+	loadCh     chan loadResult
+}
+
+// NewGrid builds a grid over an initial page of wallpapers. p supplies further
+// pages up to lastPage as the user scrolls; it may be nil for a fixed list.
+// Each page's Meta.LastPage replaces lastPage, so providers that page by
+// cursor can report one more page at a time.
+func NewGrid(wallpapers []provider.Wallpaper, r renderer.ImageRenderer, p provider.Provider, lastPage int, o Options) *Grid {
+	tmp, _ := os.MkdirTemp("", "vista-thumbs-*")
+	if o.SlideshowInterval <= 0 {
+		o.SlideshowInterval = defaultSlideInterval
+	}
+	if o.TryDuration <= 0 {
+		o.TryDuration = defaultTryDuration
+	}
+	if o.Logger == nil {
+		o.Logger = log.New(io.Discard, "", 0)
+	}
+	if o.Gap < 0 {
+		o.Gap = 0
+	}
+	border, ok := borderStyles[o.BorderStyle]
+	if !ok {
+		border = borderStyles[defaultBorderStyle]
+	}
+	fill := unicodeFill
+	if o.ASCII {
+		border = borderStyles["ascii"]
+		fill = asciiFill
+	}
+	selBorder := border
+	if o.Selection == selectThick {
+		selBorder = thickBorder
+		if o.ASCII {
+			selBorder = thickASCIIBorder
+		}
+	}
+	var review *reviewState
+	if o.Review {
+		review = newReviewState()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Grid{
+		ctx:         ctx,
+		cancel:      cancel,
+		wallpapers:  wallpapers,
+		thumbPaths:  make([]string, len(wallpapers)),
+		renderer:    r,
+		downloadDir: o.DownloadDir,
+		setOpts: wallpaper.Options{
+			Script:      wallpaper.Script{Command: o.Script, Shell: o.ScriptShell, Timeout: o.ScriptTimeout, Log: o.Logger.Printf},
+			Transition:  o.Transition,
+			Overlays:    o.Overlays,
+			OverlayDir:  o.OverlayDir,
+			CurrentFile: o.CurrentFile,
+			HistoryFile: o.HistoryFile,
+		},
+		repeatWindow: o.RepeatWindow,
+		favorites:    o.Favorites,
+		playlists:    o.Playlists,
+		ratings:      o.Ratings,
+		detailer:     o.Detailer,
+		detailCh:     make(chan detailResult, 1),
+		search:       o.Search,
+		query:        o.Query,
+		firstScreen:    o.FirstScreen,
+		firstScreenKey: o.FirstScreenKey,
+		purityLocked: o.PurityLocked,
+		marks:        make(map[byte]string),
+		tagCache:     make(map[string][]provider.Tag),
+		tagCh:        make(chan tagResult, tagFetchWorkers),
+		searchCh:     make(chan searchResult, 1),
+		thumbCh:      make(chan thumbResult, thumbRetryWorkers),
+		retrySem:     make(chan struct{}, thumbRetryWorkers),
+		tempDir:     tmp,
+		recordTo:    o.Record,
+		out:         o.Output,
+		size:        o.Size,
+		rendered:      make(map[int]string),
+		prevSelected:  -1,
+		verbose:       o.Verbose,
+		confirmQuit:   o.ConfirmQuit,
+		idleTimeout:   o.IdleTimeout,
+		stayOpen:      o.StayOpen,
+		browseOnly:    o.BrowseOnly,
+		noExec:        o.NoExec,
+		autoInfo:      o.OpenDetail,
+		cellAspect:    o.CellAspect,
+		review:        review,
+		slideInterval: o.SlideshowInterval,
+		tryDuration:   o.TryDuration,
+		tryCh:         make(chan tryState, 1),
+		thumbSize:     o.ThumbSize,
+		logger:        o.Logger,
+		workers:       o.DownloadWorkers,
+		prefetch:      max(o.PrefetchPages, 0),
+		maxPrefetch:   o.MaxPrefetchPages,
+		enlargeSelected: o.EnlargeSelected,
+		gapX:             o.Gap,
+		gapY:             (o.Gap + 1) / 2,
+		border:           border,
+		selBorder:        selBorder,
+		selection:        o.Selection,
+		fill:             fill,
+		borderUnselected: o.BorderUnselected,
+		selRendered:     make(map[string]string),
+		provider:      p,
+		meta:          o.Meta,
+		nextPage:    2,
+		requested:   2,
+		pending:     make(map[int]loadResult),
+		lastPage:    lastPage,
+		loadCh:      make(chan loadResult, 1),
+		statusCh:    make(chan string, 4),
+		streamCh:    make(chan streamed, 4),
+	}
+}
+
+// providerPage fetches a page from p, through PageContext when p supports
+// cancellation.
+func (g *Grid) providerPage(p provider.Provider, page int) ([]provider.Wallpaper, provider.Meta, error) {
+	if cp, ok := p.(provider.ContextProvider); ok {
+		return cp.PageContext(g.ctx, page)
+	}
+	return p.Page(page)
+}
+
+// lookupDetails fetches the details of id, through GetWallpaperContext
+// when the detailer supports cancellation.
+func (g *Grid) lookupDetails(id string) (provider.Details, error) {
+	if cd, ok := g.detailer.(provider.ContextDetailer); ok {
+		return cd.GetWallpaperContext(g.ctx, id)
+	}
+	return g.detailer.GetWallpaper(id)
+}
+
+func (g *Grid) Cleanup() {
+	g.cancel()
+	if c, ok := g.renderer.(io.Closer); ok {
+		c.Close()
+	}
+	os.RemoveAll(g.tempDir)
+}
+
+// clearPlacements removes images that survive a screen clear — overlay
+// windows and kitty placements — before a full repaint.
+func (g *Grid) clearPlacements(b *strings.Builder) {
+	if p, ok := g.renderer.(renderer.Placer); ok {
+		p.Clear() //nolint:errcheck
+	}
+	if c, ok := g.renderer.(renderer.Clearer); ok {
+		b.WriteString(c.ClearSequence())
+	}
+}
+
+func (g *Grid) termSize() (int, int) {
+	if g.size != nil {
+		return g.size()
+	}
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 80, 24
+	}
+	return w, h
+}
+
+func (g *Grid) layout() {
+	w, h := g.termSize()
+	aspect := g.cellAspect
+	if aspect == 0 {
+		aspect = 16.0 / 9
+		// Characters are about twice as tall as wide, so 2h > w means
+		// the window is taller than wide in pixels.
+		if 2*h > w {
+			aspect = 9.0 / 16
+		}
+	}
+	minW := minCellWidth
+	if aspect < 1 {
+		minW = portraitMinCellWidth
+	}
+	g.cols = (w + g.gapX) / (minW + g.gapX)
+	if g.cols < 1 {
+		g.cols = 1
+	}
+	g.cellW = (w - g.gapX*(g.cols-1)) / g.cols
+
+	// Derive cellH from cellW so thumbnails appear at the cell's aspect.
+	// Terminal characters are ~0.5:1 (width:height) in pixels, so a pixel-correct
+	// 16:9 image needs: cellH = cellW × (9/16) × 0.5  →  cellW × 9/32.
+	g.cellH = int(float64(g.cellW)/aspect/2 + 1e-9)
+	// A tall cell must still fit on screen with its label and the status
+	// line.
+	if maxH := h - labelHeight - statusHeight; g.cellH > maxH {
+		g.cellH = maxH
+	}
+	if g.cellH < minCellHeight {
+		g.cellH = minCellHeight
+	}
+}
+
+// resize re-runs the layout for a new terminal size. Every cached render
+// was made for the old cell size, so all of them are dropped and the screen
+// repainted.
+func (g *Grid) resize() {
+	g.layout()
+	if g.rec != nil {
+		w, h := g.termSize()
+		g.rec.event("r", fmt.Sprintf("%dx%d", w, h))
+	}
+	g.rendered = make(map[int]string)
+	g.selRendered = make(map[string]string)
+	if g.filter != nil {
+		g.filter.rendered = make(map[int]string)
+	}
+	if g.detail != nil {
+		g.detail.dirty = true
+	}
+	if g.cloud != nil {
+		g.cloud.dirty = true
+	}
+	g.ensureVisible()
+	g.prevSelected = -1 // force full redraw
+}
+
+// visibleRows returns how many grid rows fit in the terminal.
+func (g *Grid) visibleRows() int {
+	_, termH := g.termSize()
+	vr := (termH - statusHeight + g.gapY) / g.rowStride()
+	if vr < 1 {
+		vr = 1
+	}
+	return vr
+}
+
+// rowStride is the number of terminal rows from one grid row to the next.
+func (g *Grid) rowStride() int {
+	return g.cellH + labelHeight + g.gapY
+}
+
+// ensureVisible adjusts scrollRow so the selected cell is on screen.
+func (g *Grid) ensureVisible() {
+	vr := g.visibleRows()
+	selectedRow := g.selected / g.cols
+	if selectedRow < g.scrollRow {
+		g.scrollRow = selectedRow
+	} else if selectedRow >= g.scrollRow+vr {
+		g.scrollRow = selectedRow - vr + 1
+	}
+}
+
+// page moves the selection a screenful of rows down (dir 1) or up (dir -1),
+// scrolling the view with it so the selection keeps its place on screen.
+func (g *Grid) page(dir int) {
+	if len(g.wallpapers) == 0 {
+		return
+	}
+	vr := g.visibleRows()
+	lastRow := (len(g.wallpapers) - 1) / g.cols
+	g.selected = min(max(g.selected+dir*vr*g.cols, g.selected%g.cols), len(g.wallpapers)-1)
+	g.scrollRow = min(max(g.scrollRow+dir*vr, 0), max(lastRow-vr+1, 0))
+	g.ensureVisible()
+}
+
+// selectIndex selects wallpaper idx and scrolls it into view. End lands on
+// the last loaded wallpaper, which pulls in the next page if there is one.
+func (g *Grid) selectIndex(idx int) {
+	if idx < 0 || idx >= len(g.wallpapers) {
+		return
+	}
+	g.selected = idx
+	g.ensureVisible()
+}
+
+// maybeLoadMore fires a background fetch if more pages are available and
+// the viewport is close to the end of loaded content. Each page loaded
+// calls it again, so PrefetchPages pages build up one at a time.
+func (g *Grid) maybeLoadMore() {
+	if g.provider == nil || g.cols == 0 {
+		return
+	}
+	vr := g.visibleRows()
+	loadedRows := (len(g.wallpapers) + g.cols - 1) / g.cols
+	selectedRow := g.selected / g.cols
+	g.scroll.observe(selectedRow, time.Now())
+	perPage := g.meta.PerPage
+	if perPage <= 0 {
+		perPage = vr * g.cols
+	}
+	want := g.lookahead(perPage)
+	for g.requested <= g.lastPage && g.requested-g.nextPage < maxPageFetches {
+		inFlight := g.requested - g.nextPage
+		// Results loaded or on their way beyond the screenful that ends
+		// at the selection.
+		ahead := len(g.wallpapers) + inFlight*perPage - (selectedRow+vr)*g.cols
+		// Load when: loaded content doesn't fill the screen, we're within
+		// one screenful of the end, or fewer than the lookahead are buffered.
+		short := inFlight == 0 && (loadedRows < vr || selectedRow >= loadedRows-vr)
+		if !short && ahead >= want*perPage {
+			return
+		}
+		go g.fetchPage(g.gen, g.requested)
+		g.requested++
+	}
+}
+
+func (g *Grid) fetchPage(gen, page int) {
+	wallpapers, meta, err := g.providerPage(g.provider, page)
+	if err != nil {
+		// The client already retried; skip this page rather than retrying
+		// it on every redraw, and say so.
+		g.loadCh <- loadResult{gen: gen, page: page, nextPage: page + 1, err: fmt.Errorf("page %d: %w", page, err)}
+		return
+	}
+	urls := make([]string, len(wallpapers))
+	for i, wp := range wallpapers {
+		urls[i] = g.thumbURL(wp)
+	}
+	thumbPaths := wallpaper.DownloadAllContext(g.ctx, urls, g.tempDir, g.workers)
+	g.retryThumbs(urls, thumbPaths)
+	g.loadCh <- loadResult{
+		gen:        gen,
+		page:       page,
+		meta:       meta,
+		wallpapers: wallpapers,
+		thumbPaths: thumbPaths,
+		nextPage:   page + 1,
+	}
+}
+
+// thumbURL returns the configured thumbnail for wp, falling back to the small
+// thumb when the provider doesn't supply the requested size.
+func (g *Grid) thumbURL(wp provider.Wallpaper) string {
+	var u string
+	switch g.thumbSize {
+	case "large":
+		u = wp.Thumbs.Large
+	case "original":
+		u = wp.Thumbs.Original
+	}
+	if u == "" {
+		u = wp.Thumbs.Small
+	}
+	return u
+}
+
+// toggleFavorite stars or unstars the selected wallpaper.
+func (g *Grid) toggleFavorite() {
+	if g.favorites == nil || len(g.wallpapers) == 0 {
+		return
+	}
+	wp := g.wallpapers[g.selected]
+	starred, err := g.favorites.Toggle(wp)
+	switch {
+	case err != nil:
+		g.status = fmt.Sprintf(i18n.T("Saving favorites failed: %v"), err)
+	case starred:
+		g.status = fmt.Sprintf(i18n.T("Added %s to favorites"), wp.ID)
+	default:
+		g.status = fmt.Sprintf(i18n.T("Removed %s from favorites"), wp.ID)
+	}
+	g.prevSelected = -1 // repaint so the label star updates
+}
+
+// queueSelected appends the selected wallpaper to the "queue" playlist
+// played by `vista play queue`.
+func (g *Grid) queueSelected() {
+	if g.playlists == nil || len(g.wallpapers) == 0 {
+		return
+	}
+	wp := g.wallpapers[g.selected]
+	n, err := g.playlists.Append(store.QueuePlaylist, store.PlaylistItem{ID: wp.ID, Path: wp.Path})
+	if err != nil {
+		g.status = fmt.Sprintf(i18n.T("Saving playlist failed: %v"), err)
+		return
+	}
+	g.status = fmt.Sprintf(i18n.T("Queued %s (%d in queue)"), wp.ID, n)
+}
+
+// setInfo describes wp to wallpaper.Set, including any attribution.
+func setInfo(wp provider.Wallpaper) wallpaper.Info {
+	return wallpaper.Info{ID: wp.ID, URL: wp.URL, Author: wp.Author, AuthorURL: wp.AuthorURL, Source: wp.Source}
+}
+
+// pickRandom returns a random index other than the selection, avoiding
+// wallpapers among the last repeatWindow set while enough others remain.
+func (g *Grid) pickRandom() int {
+	keys := make([]string, len(g.wallpapers))
+	for i, wp := range g.wallpapers {
+		keys[i] = wallpaper.SavedKey(wp.Path, wp.Source, wp.ID)
+	}
+	var recent []string
+	if g.setOpts.HistoryFile != "" && g.repeatWindow > 0 {
+		recent = wallpaper.RecentKeys(g.setOpts.HistoryFile, g.repeatWindow)
+	}
+	// The current selection counts as the most recent so it's never picked
+	// while anything else is available.
+	recent = append(recent, keys[g.selected])
+	return wallpaper.PickUnrepeated(keys, recent, len(recent))
+}
+
+func (g *Grid) setWallpaperBg(wp provider.Wallpaper) {
+	atomic.AddInt32(&g.inflight, 1)
+	defer atomic.AddInt32(&g.inflight, -1)
+	downloaded, scans := false, 0
+	ctx := wallpaper.WithProgress(g.ctx, func(done, total int64) {
+		downloaded = true
+		g.notify(fmt.Sprintf(i18n.T("Downloading %s"), wp.ID) + "  " + ProgressBar(done, total))
+	})
+	ctx = g.streamScans(ctx, wp, &scans)
+	path, err := wallpaper.DownloadVerifiedContext(ctx, wp.Path, g.downloadDir, wallpaper.FileName(wp.Path, wp.Source, wp.ID), wp.Checksum)
+	if err == nil && scans > 0 {
+		g.sendStreamed(streamed{id: wp.ID, path: path}) // the finished original replaces the last scan
+	}
+	if err == nil {
+		err = wallpaper.Set(path, setInfo(wp), g.setOpts)
+	}
+	switch {
+	case err != nil:
+		g.logger.Printf("set %s: %v", wp.ID, err)
+		g.notify(fmt.Sprintf(i18n.T("Failed to set %s: %v"), wp.ID, err))
+	case downloaded:
+		g.notify(i18n.T("Wallpaper set!")) // replace the finished progress bar
+	}
+}
+
+// undoBg restores the previous wallpaper in the background.
+func (g *Grid) undoBg() {
+	e, err := wallpaper.Undo(g.setOpts)
+	switch {
+	case errors.Is(err, wallpaper.ErrNothingToUndo):
+		g.notify(i18n.T("nothing to undo"))
+	case err != nil:
+		g.logger.Printf("undo: %v", err)
+		g.notify(fmt.Sprintf(i18n.T("Undo failed: %v"), err))
+	default:
+		g.notify(fmt.Sprintf(i18n.T("Restored %s"), filepath.Base(e.Path)))
+	}
+}
+
+// notify posts a status message from a background goroutine. Messages are
+// dropped rather than blocking if the UI is behind.
+func (g *Grid) notify(msg string) {
+	select {
+	case g.statusCh <- msg:
+	default:
+	}
+}
+
+// Run starts the interactive UI. Returns the path of the selected wallpaper
+// if the user pressed Enter, or "" if they quit.
+func (g *Grid) Run() (string, error) {
+	// Put terminal in raw mode
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("raw mode: %w", err)
+	}
+	defer term.Restore(int(os.Stdin.Fd()), oldState)
+	defer g.cancel()
+
+	g.layout()
+	if g.recordTo != nil {
+		w, h := g.termSize()
+		g.rec = newRecorder(g.recordTo, w, h)
+	}
+	resizeCh := make(chan os.Signal, 1)
+	notifyResize(resizeCh)
+	defer signal.Stop(resizeCh)
+
+	// Hide cursor
+	g.print("\033[?25l")
+	defer g.print("\033[?25h")
+
+	// Pre-download first page thumbnails (blocking)
+	g.prefetchThumbs()
+	if g.searchGen == 0 {
+		// Fresh results, not a saved screen awaiting Refresh.
+		go g.saveFirstScreen(g.meta, slices.Clone(g.wallpapers), slices.Clone(g.thumbPaths))
+	}
+
+	// Read stdin in a goroutine so the main loop can also wait on loadCh.
+	inputCh := make(chan []byte, 10)
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(inputCh)
+				return
+			}
+			tmp := make([]byte, n)
+			copy(tmp, buf[:n])
+			inputCh <- tmp
+		}
+	}()
+
+	// A nil channel blocks forever, so the idle case never fires when disabled.
+	var idle *time.Timer
+	var idleC <-chan time.Time
+	if g.idleTimeout > 0 {
+		idle = time.NewTimer(g.idleTimeout)
+		defer idle.Stop()
+		idleC = idle.C
+	}
+
+	var slide *time.Ticker
+	var slideC <-chan time.Time
+	defer func() {
+		if slide != nil {
+			slide.Stop()
+		}
+	}()
+
+	// Ticks once a second while a 't' trial counts down.
+	var tryTick *time.Ticker
+	var tryC <-chan time.Time
+	defer func() {
+		if tryTick != nil {
+			tryTick.Stop()
+		}
+		// Leaving mid-trial counts as not confirming it.
+		g.endTry(false, true)
+	}()
+
+	if g.autoInfo && len(g.wallpapers) > 0 {
+		g.openDetail()
+	}
+	g.draw()
+	g.maybeLoadMore()
+
+	for {
+		select {
+		case key, ok := <-inputCh:
+			if !ok {
+				return "", nil
+			}
+			g.rec.event("i", string(key))
+			if idle != nil {
+				idle.Reset(g.idleTimeout)
+			}
+			g.status = ""
+			if g.prompting {
+				g.handlePromptKey(key)
+				break
+			}
+			if g.markPrefix != 0 {
+				g.handleMarkKey(key)
+				break
+			}
+			if g.goPending {
+				g.goPending = false
+				if len(key) == 1 && key[0] == 'g' {
+					g.selectIndex(0)
+					break
+				}
+			}
+			action := parseKey(key)
+			if g.detail != nil && action == actionSelect && !g.browseOnly {
+				g.closeDetail() // enter sets the wallpaper shown, as from the grid
+			}
+			if g.detail != nil && action != actionQuit {
+				if action == actionInfo || action == actionEscape {
+					g.closeDetail()
+				} else if action == actionOpen && g.detail.wp.URL != "" {
+					g.openURL(g.detail.wp.URL)
+				} else if action == actionUndo && g.detail.info != nil && g.detail.info.Uploader != "" {
+					g.searchUploader(g.detail.info.Uploader) // u: more by this uploader
+				} else if action == actionSimilar {
+					g.searchSimilar(g.detail.wp)
+				}
+				break
+			}
+			if g.cloud != nil && action != actionQuit {
+				g.handleTagCloudKey(action)
+				break
+			}
+			if g.trying != nil && (action == actionSelect || action == actionEscape) {
+				g.endTry(action == actionSelect, false)
+				break
+			}
+			if g.quitPending {
+				g.quitPending = false
+				if len(key) == 1 && (key[0] == 'y' || key[0] == 'Y') {
+					g.clearScreen()
+					return "", nil
+				}
+				break
+			}
+			if g.browseOnly {
+				switch action {
+				case actionSelect:
+					action = actionInfo
+				case actionSetBg, actionTry, actionUndo, actionDelete:
+					g.status = i18n.T("Browse-only mode: wallpapers can't be set or deleted")
+					action = actionNone
+				}
+			}
+			switch action {
+			case actionQuit:
+				if g.confirmQuit && atomic.LoadInt32(&g.inflight) > 0 {
+					g.quitPending = true
+					g.status = i18n.T("Downloads in progress - quit anyway? (y/n)")
+					break
+				}
+				g.clearScreen()
+				return "", nil
+
+			case actionUp:
+				if g.selected >= g.cols {
+					g.selected -= g.cols
+					g.ensureVisible()
+				}
+			case actionDown:
+				if g.selected+g.cols < len(g.wallpapers) {
+					g.selected += g.cols
+					g.ensureVisible()
+				}
+			case actionLeft:
+				if g.selected > 0 {
+					g.selected--
+					g.ensureVisible()
+				}
+			case actionRight:
+				if g.selected < len(g.wallpapers)-1 {
+					g.selected++
+					g.ensureVisible()
+				}
+
+			case actionPageUp:
+				g.page(-1)
+			case actionPageDown:
+				g.page(1)
+			case actionHome:
+				g.selectIndex(0)
+			case actionEnd:
+				g.selectIndex(len(g.wallpapers) - 1)
+			case actionGo:
+				g.goPending = true
+
+			case actionSort:
+				g.cycleSorting()
+			case actionPuritySFW, actionPuritySketchy, actionPurityNSFW:
+				g.toggleFilter(true, int(action-actionPuritySFW))
+			case actionCatGeneral, actionCatAnime, actionCatPeople:
+				g.toggleFilter(false, int(action-actionCatGeneral))
+
+			case actionRandom:
+				// Pick a different cell so the jump is always visible, and
+				// skip wallpapers set recently. Landing near the end of the
+				// loaded results also pulls in the next page via
+				// maybeLoadMore below.
+				if len(g.wallpapers) > 1 {
+					g.selected = g.pickRandom()
+					g.ensureVisible()
+				}
+
+			case actionSetBg:
+				go g.setWallpaperBg(g.wallpapers[g.selected])
+
+			case actionUndo:
+				go g.undoBg()
+
+			case actionFavorite:
+				g.toggleFavorite()
+
+			case actionQueue:
+				g.queueSelected()
+
+			case actionInfo:
+				g.openDetail()
+
+			case actionTags:
+				g.openTagCloud()
+
+			case actionSimilar:
+				g.searchSimilar(g.wallpapers[g.selected])
+
+			case actionMark, actionJump:
+				g.markPrefix = key[0]
+				g.status = i18n.T("press 1-9")
+
+			case actionRate:
+				if g.ratings != nil {
+					g.markPrefix = key[0]
+					g.status = i18n.T("press 1-5, or 0 to clear")
+				}
+
+			case actionNote:
+				if g.ratings != nil {
+					g.prompting = true
+					g.noting = true
+					g.promptText = g.ratings.Get(store.RatingKey(g.wallpapers[g.selected])).Note
+				}
+
+			case actionTry:
+				if g.trying == nil {
+					go g.startTry(g.wallpapers[g.selected])
+				}
+
+			case actionKeep:
+				if g.review != nil {
+					g.keepSelected()
+				}
+
+			case actionDelete:
+				wp := g.wallpapers[g.selected]
+				if !wp.Local() {
+					break // only delete local files
+				}
+				if g.review != nil {
+					g.reviewDeleted(wp)
+				}
+				os.Remove(wp.Path)
+				g.forgetFiltered(wp.Path)
+				// Re-key the render cache so indices remain valid.
+				newRendered := make(map[int]string)
+				for k, v := range g.rendered {
+					if k < g.selected {
+						newRendered[k] = v
+					} else if k > g.selected {
+						newRendered[k-1] = v
+					}
+				}
+				g.rendered = newRendered
+				g.wallpapers = append(g.wallpapers[:g.selected], g.wallpapers[g.selected+1:]...)
+				g.thumbPaths = append(g.thumbPaths[:g.selected], g.thumbPaths[g.selected+1:]...)
+				if len(g.wallpapers) == 0 && g.filter != nil {
+					g.clearFilter()
+					break
+				}
+				if len(g.wallpapers) == 0 {
+					g.clearScreen()
+					return "", nil
+				}
+				if g.selected >= len(g.wallpapers) {
+					g.selected = len(g.wallpapers) - 1
+				}
+				g.ensureVisible()
+				g.prevSelected = -1
+
+			case actionFilter:
+				g.prompting = true
+				g.promptText = ""
+				if g.filter != nil {
+					g.promptText = g.filter.query
+				}
+
+			case actionSlideshow:
+				g.toggleSlideshow()
+
+			case actionEscape:
+				if g.slideshow {
+					g.toggleSlideshow()
+					break
+				}
+				g.clearFilter()
+
+			case actionHelp:
+				g.showHelp = !g.showHelp
+				g.prevSelected = -1 // force full redraw
+
+			case actionOpen:
+				if url := g.wallpapers[g.selected].URL; url != "" {
+					g.openURL(url)
+				}
+
+			case actionSelect:
+				if g.stayOpen {
+					go g.setWallpaperBg(g.wallpapers[g.selected])
+					break
+				}
+				g.clearScreen()
+				term.Restore(int(os.Stdin.Fd()), oldState)
+				g.print("\033[?25h")
+
+				wp := g.wallpapers[g.selected]
+				if g.verbose {
+					fmt.Printf(i18n.T("Applying %s...")+"\n", wp.ID)
+				}
+				ctx := g.ctx
+				if term.IsTerminal(int(os.Stdout.Fd())) {
+					ctx = wallpaper.WithProgress(ctx, PrintProgress(os.Stdout, fmt.Sprintf(i18n.T("Downloading %s"), wp.ID)))
+				}
+				path, err := wallpaper.DownloadVerifiedContext(ctx, wp.Path, g.downloadDir, wallpaper.FileName(wp.Path, wp.Source, wp.ID), wp.Checksum)
+				if err != nil {
+					return "", fmt.Errorf("downloading wallpaper: %w", err)
+				}
+				if g.verbose {
+					fmt.Printf(i18n.T("Setting wallpaper: %s")+"\n", path)
+				}
+				if err := wallpaper.Set(path, setInfo(wp), g.setOpts); err != nil {
+					return "", fmt.Errorf("setting wallpaper: %w", err)
+				}
+				if g.verbose {
+					fmt.Println(i18n.T("Wallpaper set!"))
+				}
+				return path, nil
+			}
+
+		case result := <-g.loadCh:
+			if result.gen != g.gen {
+				break // a page of results replaced by a search
+			}
+			g.applyLoaded(result)
+
+		case <-resizeCh:
+			g.resize()
+
+		case msg := <-g.statusCh:
+			g.status = msg
+
+		case s := <-g.streamCh:
+			g.applyStreamed(s)
+
+		case res := <-g.detailCh:
+			g.applyDetail(res)
+
+		case res := <-g.tagCh:
+			g.applyTags(res)
+
+		case res := <-g.searchCh:
+			g.applySearch(res)
+
+		case res := <-g.thumbCh:
+			g.applyThumb(res)
+
+		case t := <-g.tryCh:
+			t.deadline = time.Now().Add(g.tryDuration)
+			g.trying = &t
+
+		case <-tryC:
+			if g.trying != nil && !time.Now().Before(g.trying.deadline) {
+				g.endTry(false, false)
+			}
+
+		case <-slideC:
+			g.advanceSlideshow()
+
+		case <-idleC:
+			g.clearScreen()
+			return "", nil
+		}
+
+		if g.trying != nil && tryTick == nil {
+			tryTick = time.NewTicker(time.Second)
+			tryC = tryTick.C
+		} else if g.trying == nil && tryTick != nil {
+			tryTick.Stop()
+			tryTick, tryC = nil, nil
+		}
+
+		if g.slideshow && slide == nil {
+			slide = time.NewTicker(g.slideInterval)
+			slideC = slide.C
+		} else if !g.slideshow && slide != nil {
+			slide.Stop()
+			slide, slideC = nil, nil
+		}
+
+		g.draw()
+		g.maybeLoadMore()
+	}
+}
+
+func (g *Grid) prefetchThumbs() {
+	urls := make([]string, len(g.wallpapers))
+	for i, wp := range g.wallpapers {
+		if g.thumbPaths[i] == "" {
+			urls[i] = g.thumbURL(wp)
+		}
+	}
+	paths := wallpaper.DownloadAllContext(g.ctx, urls, g.tempDir, g.workers)
+	for i, p := range paths {
+		if p != "" {
+			g.thumbPaths[i] = p
+		}
+	}
+	g.retryThumbs(urls, paths)
+}
+
+func (g *Grid) draw() {
+	if s := g.render(); s != "" {
+		g.print(s)
+	}
+}
+
+// render returns the output that brings the screen up to date: a full
+// repaint or only what changed since the last call.
+func (g *Grid) render() string {
+	vr := g.visibleRows()
+
+	var b strings.Builder
+
+	if g.showHelp {
+		// Clear the screen and show only the help overlay. Trying to draw the
+		// overlay on top of pixel-protocol image placements (kitty/sixel) is
+		// unreliable — images live in a separate rendering layer and bleed
+		// through regardless of background colour. A blank canvas is simpler
+		// and guaranteed readable in every terminal.
+		g.clearPlacements(&b)
+		b.WriteString("\033[H\033[2J")
+		g.writeHelpTo(&b)
+	} else if g.detail != nil {
+		if g.detail.dirty {
+			g.writeDetailTo(&b)
+			g.detail.dirty = false
+		}
+	} else if g.cloud != nil {
+		if g.cloud.dirty {
+			g.writeTagCloudTo(&b)
+			g.cloud.dirty = false
+		}
+	} else if g.slideshow {
+		if g.selected != g.prevSelected {
+			g.writePreviewTo(&b)
+		}
+	} else {
+		// With enlarged selection the old and new selected images change
+		// size, and pixel-protocol images aren't erased by drawing a smaller
+		// one on top, so a selection change needs a full repaint.
+		needFull := g.prevSelected < 0 ||
+			g.scrollRow != g.prevScrollRow ||
+			len(g.wallpapers) != g.prevCount ||
+			(g.enlargeSelected && g.selected != g.prevSelected)
+
+		if needFull {
+			// Full repaint: accumulate into a buffer and write in one shot to
+			// minimise the visible blank-screen window.
+			g.renderVisible(vr)
+			g.clearPlacements(&b)
+			b.WriteString("\033[H\033[2J")
+			for idx := range g.wallpapers {
+				g.writeCellTo(&b, idx, vr)
+			}
+		} else if g.selected != g.prevSelected {
+			// Only the selection changed — repaint just the two affected cells.
+			// No screen clear, so there is no flash at all.
+			g.writeCellTo(&b, g.prevSelected, vr)
+			g.writeCellTo(&b, g.selected, vr)
+		}
+		if !needFull {
+			for _, idx := range g.refreshed {
+				g.writeCellTo(&b, idx, vr)
+			}
+		}
+		g.refreshed = nil
+	}
+
+	status := g.statusLine()
+	if status != g.prevStatus || b.Len() > 0 {
+		g.writeStatusTo(&b, status)
+	}
+
+	if b.Len() > 0 {
+		// Park cursor; the caller flushes everything in one write.
+		fmt.Fprintf(&b, "\033[%d;1H", vr*g.rowStride()+1)
+	}
+
+	g.prevStatus = status
+	g.prevSelected = g.selected
+	g.prevScrollRow = g.scrollRow
+	g.prevCount = len(g.wallpapers)
+	return b.String()
+}
+
+// writeCellTo renders a single cell (image + selection border + label) into b.
+// It is a no-op if the cell is outside the current viewport.
+func (g *Grid) writeCellTo(b *strings.Builder, idx int, vr int) {
+	if idx < 0 || idx >= len(g.wallpapers) {
+		return
+	}
+	row := idx / g.cols
+	if row < g.scrollRow || row >= g.scrollRow+vr {
+		return
+	}
+	col := idx % g.cols
+
+	// terminal coordinates are 1-based
+	startRow := (row-g.scrollRow)*g.rowStride() + 1
+	startCol := col*(g.cellW+g.gapX) + 1
+
+	thumbPath := ""
+	if idx < len(g.thumbPaths) {
+		thumbPath = g.thumbPaths[idx]
+	}
+
+	// Write the image line by line with explicit cursor positioning.
+	// For pixel protocols (kitty/sixel/iterm) the rendered string has no
+	// raw newlines, so this reduces to a single write at the cell origin.
+	// For symbols/character-art each line must be explicitly positioned.
+	_, _, rowOff, colOff := g.imageBox(idx)
+	imgLines := strings.Split(strings.TrimRight(g.imageStr(idx, thumbPath), "\n"), "\n")
+	for i, line := range imgLines {
+		fmt.Fprintf(b, "\033[%d;%dH%s", startRow+rowOff+i, startCol+colOff, line)
+	}
+	if p, ok := g.renderer.(renderer.Placer); ok && thumbPath != "" {
+		// Overlay windows sit above the text, so keep the top row free for
+		// the selection border.
+		id := fmt.Sprintf("cell-%d", idx)
+		w, h, _, _ := g.imageBox(idx)
+		if err := p.Place(id, thumbPath, startCol-1+colOff, startRow+rowOff, w, h-1); err != nil {
+			g.logger.Printf("place %s: %v", g.wallpapers[idx].ID, err)
+		}
+	}
+
+	// Top border — drawn after the image so it always sits on top.
+	if idx == g.selected || g.borderUnselected {
+		frame, color := g.cellFrame(idx)
+		topBar := frame.tl + strings.Repeat(frame.h, g.cellW-2) + frame.tr
+		fmt.Fprintf(b, "\033[%d;%dH%s%s%s", startRow, startCol, color, topBar, resetColor)
+	}
+
+	// Label — always at a fixed offset below the cell origin.
+	wp := g.wallpapers[idx]
+	fmt.Fprintf(b, "\033[%d;%dH%s", startRow+g.cellH, startCol, g.formatLabel(idx, g.labelText(wp)))
+}
+
+// statusLine returns the text for the status line, most urgent first.
+func (g *Grid) statusLine() string {
+	switch {
+	case g.prompting && g.noting:
+		return i18n.T("note:") + " " + g.promptText + "_"
+	case g.prompting:
+		return i18n.T("filter:") + " " + g.promptText + "_"
+	case g.status != "":
+		return g.status
+	case g.detail != nil:
+		hint := i18n.T("i/esc back  o open in browser")
+		if g.detail.info != nil && g.detail.info.Uploader != "" && g.search != nil {
+			hint = i18n.T("i/esc back  o open in browser  u more by uploader")
+		}
+		if g.search != nil {
+			hint += "  " + i18n.T("L similar")
+		}
+		if !g.browseOnly {
+			hint = i18n.T("enter set") + "  " + hint
+		}
+		return hint
+	case g.cloud != nil:
+		return i18n.T("arrows move  enter search tag  c/esc back")
+	case g.trying != nil:
+		return g.tryStatus()
+	case g.slideshow && len(g.wallpapers) > 0:
+		return g.slideshowStatus()
+	case g.filter != nil:
+		return fmt.Sprintf(i18n.T("filter %q: %d of %d  (esc to clear)"),
+			g.filter.query, len(g.wallpapers), len(g.filter.wallpapers))
+	case g.review != nil:
+		return g.reviewStatus()
+	case g.meta.Total > 0:
+		return g.metaStatus()
+	}
+	return ""
+}
+
+// metaStatus summarises the search: the page strip, results loaded of the
+// total, page size, sorting and, for random sorting, the seed.
+func (g *Grid) metaStatus() string {
+	m := g.meta
+	s := g.pageStrip() + "  " + fmt.Sprintf(i18n.T("%d of %d loaded"), len(g.wallpapers), m.Total)
+	if m.PerPage > 0 {
+		s += "  " + fmt.Sprintf(i18n.T("%d per page"), m.PerPage)
+	}
+	if g.query != nil && g.query.Sorting != "" {
+		s += "  " + fmt.Sprintf(i18n.T("Sorted by %s"), g.query.Sorting)
+	}
+	if m.Seed != "" {
+		s += "  " + fmt.Sprintf(i18n.T("seed %s"), m.Seed)
+	}
+	return s
+}
+
+// pageStripWidth caps the segments in the page strip; beyond it each
+// segment stands for several pages.
+const pageStripWidth = 20
+
+// pageStrip shows where the selection sits in the result set, e.g.
+// "▰▮▰▱▱ page 2/5": a segment per page, marking those loaded and the one
+// on screen.
+func (g *Grid) pageStrip() string {
+	m := g.meta
+	last := max(m.LastPage, 1)
+	loaded := min(g.nextPage-1, last)
+	perPage := m.PerPage
+	if perPage <= 0 {
+		perPage = max(len(g.wallpapers)/max(loaded, 1), 1)
+	}
+	here := min(g.selected/perPage+1, last)
+
+	n := min(last, pageStripWidth)
+	var b strings.Builder
+	for seg := range n {
+		switch {
+		case seg == (here-1)*n/last:
+			b.WriteString(g.fill.pageHere)
+		case seg*last/n+1 <= loaded:
+			b.WriteString(g.fill.pageDone)
+		default:
+			b.WriteString(g.fill.pageTodo)
+		}
+	}
+	return b.String() + " " + fmt.Sprintf(i18n.T("page %d/%d"), here, last)
+}
+
+// writeStatusTo draws the status line on the bottom terminal row.
+func (g *Grid) writeStatusTo(b *strings.Builder, status string) {
+	_, h := g.termSize()
+	fmt.Fprintf(b, "\033[%d;1H\033[2K", h)
+	if status != "" {
+		fmt.Fprintf(b, "\033[1;93m%s\033[0m", status)
+	}
+}
+
+// renderVisible renders every uncached on-screen thumbnail in parallel,
+// bounded by the CPU count, so a full repaint doesn't wait on one chafa
+// process after another.
+func (g *Grid) renderVisible(vr int) {
+	first := g.scrollRow * g.cols
+	last := min((g.scrollRow+vr)*g.cols, len(g.wallpapers))
+
+	type job struct {
+		idx int
+		out string
+	}
+	var jobs []*job
+	for idx := first; idx < last; idx++ {
+		if _, ok := g.rendered[idx]; ok || idx >= len(g.thumbPaths) || g.thumbPaths[idx] == "" {
+			continue
+		}
+		if g.enlargeSelected && idx == g.selected {
+			continue // full-size render, cached separately by imageStr
+		}
+		jobs = append(jobs, &job{idx: idx})
+	}
+	if len(jobs) < 2 {
+		return // imageStr handles a single render just as well
+	}
+
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			w, h, _, _ := g.imageBox(j.idx)
+			out, err := g.renderer.Render(g.thumbPaths[j.idx], w, h)
+			if err != nil {
+				g.logger.Printf("render %s: %v", g.wallpapers[j.idx].ID, err)
+				out = g.failedLines(w, h)
+			}
+			j.out = out
+		}()
+	}
+	wg.Wait()
+
+	for _, j := range jobs {
+		g.rendered[j.idx] = j.out
+	}
+}
+
+// imageBox returns the size of the image within cell idx and its offset
+// from the cell origin. Only EnlargeSelected makes these differ per cell.
+func (g *Grid) imageBox(idx int) (w, h, rowOff, colOff int) {
+	if g.enlargeSelected && idx != g.selected {
+		return g.cellW - 2, g.cellH - 1, 1, 1
+	}
+	return g.cellW, g.cellH, 0, 0
+}
+
+func (g *Grid) imageStr(idx int, thumbPath string) string {
+	w, h, _, _ := g.imageBox(idx)
+	if thumbPath == "" {
+		return g.placeholderLines(w, h)
+	}
+	cache := g.rendered[idx]
+	if g.enlargeSelected && idx == g.selected {
+		cache = g.selRendered[thumbPath]
+	}
+	if cache != "" {
+		return cache
+	}
+	rendered, err := g.renderer.Render(thumbPath, w, h)
+	if err != nil {
+		g.logger.Printf("render %s: %v", g.wallpapers[idx].ID, err)
+		rendered = g.failedLines(w, h)
+	}
+	if g.enlargeSelected && idx == g.selected {
+		g.selRendered[thumbPath] = rendered
+	} else {
+		g.rendered[idx] = rendered
+	}
+	return rendered
+}
+
+// labelText is the text under a cell: the resolution, starred for favorites
+// and prefixed with any rating and marks.
+func (g *Grid) labelText(wp provider.Wallpaper) string {
+	label := wp.Resolution
+	if g.review != nil {
+		label += "  " + humanSize(g.review.size(wp))
+		if g.review.kept[wp.Path] {
+			label = g.fill.check + " " + label
+		}
+	}
+	if g.favorites != nil && g.favorites.Has(wp) {
+		label = g.fill.star + " " + label
+	}
+	if r := g.ratingLabel(wp); r != "" {
+		label = r + " " + label
+	}
+	if m := g.markLabel(wp); m != "" {
+		label = m + " " + label
+	}
+	return label
+}
+
+func (g *Grid) formatLabel(idx int, resolution string) string {
+	if idx == g.selected || g.borderUnselected {
+		// ╚═  1920x1080  ═╝  — bottom half of the cell frame
+		frame, color := g.cellFrame(idx)
+		if idx == g.selected && g.selection == selectArrow {
+			resolution = g.fill.arrowL + " " + resolution + " " + g.fill.arrowR
+		}
+		inner := centerPad(resolution, g.cellW-4)
+		return color + frame.bl + frame.h + inner + frame.h + frame.br + resetColor
+	}
+	return " " + centerPad(resolution, g.cellW-2) + " "
+}
+
+// cellFrame returns the frame characters and colour for cell idx, styled
+// by Options.Selection when it is selected.
+func (g *Grid) cellFrame(idx int) (borderStyle, string) {
+	switch {
+	case idx != g.selected:
+		return g.border, unselectedColor
+	case g.selection == selectInverse:
+		return g.selBorder, selectedColor + inverseVideo
+	}
+	return g.selBorder, selectedColor
+}
+
+func (g *Grid) placeholderLines(w, h int) string {
+	var sb strings.Builder
+	for i := 0; i < h; i++ {
+		sb.WriteString(strings.Repeat(g.fill.pending, w) + "\n")
+	}
+	return sb.String()
+}
+
+// failedLines is the placeholder for a thumbnail the renderer rejected,
+// distinct from the fill used while a thumbnail is still missing.
+func (g *Grid) failedLines(w, h int) string {
+	var sb strings.Builder
+	for i := 0; i < h; i++ {
+		if i == h/2 {
+			sb.WriteString(centerPad(i18n.T("render failed"), w) + "\n")
+			continue
+		}
+		sb.WriteString(strings.Repeat(g.fill.failed, w) + "\n")
+	}
+	return sb.String()
+}
+
+func centerPad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n >= width {
+		return string([]rune(s)[:max(width, 0)])
+	}
+	total := width - utf8.RuneCountInString(s)
+	left := total / 2
+	right := total - left
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", right)
+}
+
+func (g *Grid) writeHelpTo(b *strings.Builder) {
+	w, h := g.termSize()
+
+	// Colour scheme: dark background so the box is opaque over images.
+	const (
+		bg     = "\033[48;5;235m" // dark grey background
+		border = "\033[48;5;235m\033[1;96m" // bright cyan border on dark bg
+		text   = "\033[48;5;235m\033[97m"   // bright white text on dark bg
+		reset  = "\033[0m"
+	)
+
+	title := " " + i18n.T("KEYS") + " "
+	rows := g.helpRows()
+
+	maxW := utf8.RuneCountInString(title)
+	for _, r := range rows {
+		if n := utf8.RuneCountInString(r); n > maxW {
+			maxW = n
+		}
+	}
+
+	// inner = content width (1 space padding each side); box outer = inner + 2 borders
+	inner := maxW + 2
+	boxH := len(rows) + 2
+
+	startRow := (h-boxH)/2 + 1
+	startCol := (w-inner-2)/2 + 1
+
+	// Top border with centred title
+	titlePad := inner - utf8.RuneCountInString(title)
+	lPad := titlePad / 2
+	rPad := titlePad - lPad
+	bs := g.border
+	fmt.Fprintf(b, "\033[%d;%dH%s%s%s%s%s%s%s",
+		startRow, startCol, border, bs.tl,
+		strings.Repeat(bs.h, lPad), title, strings.Repeat(bs.h, rPad), bs.tr, reset)
+
+	// Content rows — bg covers full width so images don't bleed through
+	for i, row := range rows {
+		fmt.Fprintf(b, "\033[%d;%dH%s%s%s %-*s %s%s%s",
+			startRow+1+i, startCol,
+			border, bs.v, text, maxW, row, border, bs.v, reset)
+	}
+
+	// Bottom border
+	fmt.Fprintf(b, "\033[%d;%dH%s%s%s%s%s",
+		startRow+1+len(rows), startCol, border, bs.bl, strings.Repeat(bs.h, inner), bs.br, reset)
+}
+
+// helpRows returns the key bindings shown in the help overlay.
+func (g *Grid) helpRows() []string {
+	enter := "download + set"
+	if g.stayOpen {
+		enter = "set (stay open)"
+	}
+	if g.browseOnly {
+		enter = "details"
+	}
+	keys := [][2]string{
+		{"arrows / hjkl", "navigate"},
+		{"pgup / pgdn", "scroll a screen"},
+		{"home / gg", "first wallpaper"},
+		{"end / G", "last loaded wallpaper"},
+		{"enter", enter},
+		{"s", "set (stay open)"},
+		{"t", "try on desktop, revert unless kept"},
+		{"u", "undo the last wallpaper change"},
+		{"f", "toggle favorite"},
+		{"a", "add to the play queue"},
+		{"r0-r5", "rate the selection, 0 clears"},
+		{"n", "note on the selection"},
+		{"i", "details"},
+		{"c", "tag cloud of loaded results"},
+		{"L", "find similar wallpapers"},
+		{"S", "next sorting: relevance, toplist, hot, newest, random"},
+		{"1 2 3", "toggle sfw, sketchy, nsfw and search again"},
+		{"F1 F2 F3", "toggle general, anime, people and search again"},
+		{"m1-m9", "mark the selection"},
+		{"'1-'9", "jump to a mark"},
+		{"o", "open in browser"},
+		{"*", "jump to random"},
+		{"p", "slideshow preview"},
+		{"d", "delete (local files)"},
+		{"space", "keep and move on (review)"},
+		{"ctrl-f", "filter loaded results"},
+		{"esc", "clear filter"},
+		{"?", "toggle help"},
+		{"q", "quit"},
+	}
+	rows := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if g.browseOnly && (k[0] == "s" || k[0] == "t" || k[0] == "u" || k[0] == "d") {
+			continue
+		}
+		if g.review == nil && k[0] == "space" {
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("%-15s %s", k[0], i18n.T(k[1])))
+	}
+	return rows
+}
+
+// openURL opens url in the default browser, or with NoExec shows it for
+// the user to open.
+func (g *Grid) openURL(url string) {
+	if g.noExec {
+		g.status = fmt.Sprintf(i18n.T("--no-exec: open %s yourself"), url)
+		return
+	}
+	var cmd string
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = "open"
+	case "windows":
+		cmd = "cmd /c start"
+	default:
+		cmd = "xdg-open"
+	}
+	exec.Command(cmd, url).Start() //nolint:errcheck
+}
+
+func (g *Grid) clearScreen() {
+	g.print("\033[H\033[2J")
+}
+
+// Key actions
+type keyAction int
+
+const (
+	actionNone keyAction = iota
+	actionUp
+	actionDown
+	actionLeft
+	actionRight
+	actionPageUp
+	actionPageDown
+	actionHome
+	actionEnd
+	actionGo // first key of gg
+	actionSort
+	actionPuritySFW
+	actionPuritySketchy
+	actionPurityNSFW
+	actionCatGeneral
+	actionCatAnime
+	actionCatPeople
+	actionSelect
+	actionSetBg
+	actionTry
+	actionUndo
+	actionFavorite
+	actionQueue
+	actionRate
+	actionNote
+	actionInfo
+	actionTags
+	actionSimilar
+	actionMark
+	actionJump
+	actionDelete
+	actionKeep
+	actionOpen
+	actionRandom
+	actionSlideshow
+	actionHelp
+	actionFilter
+	actionEscape
+	actionQuit
+)
+
+func parseKey(b []byte) keyAction {
+	if len(b) == 0 {
+		return actionNone
+	}
+
+	// Single byte keys
+	if len(b) == 1 {
+		switch b[0] {
+		case 'q', 3: // q or Ctrl+C
+			return actionQuit
+		case '\r', '\n':
+			return actionSelect
+		case 'h':
+			return actionLeft
+		case 'j':
+			return actionDown
+		case 'k':
+			return actionUp
+		case 'l':
+			return actionRight
+		case 'g':
+			return actionGo
+		case 'S':
+			return actionSort
+		case '1':
+			return actionPuritySFW
+		case '2':
+			return actionPuritySketchy
+		case '3':
+			return actionPurityNSFW
+		case 'G':
+			return actionEnd
+		case 's':
+			return actionSetBg
+		case 't':
+			return actionTry
+		case 'u':
+			return actionUndo
+		case 'f':
+			return actionFavorite
+		case 'a':
+			return actionQueue
+		case 'r':
+			return actionRate
+		case 'n':
+			return actionNote
+		case 'i':
+			return actionInfo
+		case 'c':
+			return actionTags
+		case 'L':
+			return actionSimilar
+		case 'm':
+			return actionMark
+		case '\'':
+			return actionJump
+		case 'd':
+			return actionDelete
+		case ' ':
+			return actionKeep
+		case 'o':
+			return actionOpen
+		case '*':
+			return actionRandom
+		case 'p':
+			return actionSlideshow
+		case '?':
+			return actionHelp
+		case 6: // Ctrl+F
+			return actionFilter
+		case 27:
+			return actionEscape
+		}
+	}
+
+	// Escape sequences
+	if len(b) >= 3 && b[0] == '\033' && b[1] == '[' {
+		switch b[2] {
+		case 'A':
+			return actionUp
+		case 'B':
+			return actionDown
+		case 'C':
+			return actionRight
+		case 'D':
+			return actionLeft
+		case 'H':
+			return actionHome
+		case 'F':
+			return actionEnd
+		}
+		// ESC [ n ~ keys; Home and End vary between terminals.
+		switch string(b[2:]) {
+		case "5~":
+			return actionPageUp
+		case "6~":
+			return actionPageDown
+		case "1~", "7~":
+			return actionHome
+		case "4~", "8~":
+			return actionEnd
+		case "11~", "[A":
+			return actionCatGeneral
+		case "12~", "[B":
+			return actionCatAnime
+		case "13~", "[C":
+			return actionCatPeople
+		}
+	}
+	if len(b) == 3 && b[0] == '\033' && b[1] == 'O' {
+		switch b[2] {
+		case 'H':
+			return actionHome
+		case 'F':
+			return actionEnd
+		case 'P':
+			return actionCatGeneral
+		case 'Q':
+			return actionCatAnime
+		case 'R':
+			return actionCatPeople
+		}
+	}
+
+	return actionNone
+}
+
+// TempDir returns the temp dir used for thumbnails.
+func (g *Grid) TempDir() string {
+	return g.tempDir
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+)
+
+// maxPageFetches bounds the pages fetched at once. Each also downloads a
+// page of thumbnails, and the API client backs off on rate limiting, so
+// more in flight would only queue up behind it.
+const maxPageFetches = 2
+
+// defaultMaxPrefetch is the lookahead cap when Options.MaxPrefetchPages is
+// zero.
+const defaultMaxPrefetch = 4
+
+// lookaheadHorizon is how far ahead, in time at the current scroll speed,
+// the loaded results should reach.
+const lookaheadHorizon = 3 * time.Second
+
+// speedWindow is how far back scrolling counts towards the speed.
+const speedWindow = 2 * time.Second
+
+// scrollSpeed measures how fast the selection moves through the rows.
+type scrollSpeed struct {
+	row   int
+	moves []scrollMove // within speedWindow, oldest first
+}
+
+type scrollMove struct {
+	at   time.Time
+	rows int
+}
+
+// observe records the selection's row as of now.
+func (s *scrollSpeed) observe(row int, now time.Time) {
+	if d := row - s.row; d != 0 {
+		s.moves = append(s.moves, scrollMove{at: now, rows: abs(d)})
+		s.row = row
+	}
+	i := 0
+	for i < len(s.moves) && now.Sub(s.moves[i].at) > speedWindow {
+		i++
+	}
+	s.moves = s.moves[i:]
+}
+
+// rowsPerSecond is the scrolling speed over the last speedWindow.
+func (s *scrollSpeed) rowsPerSecond() float64 {
+	rows := 0
+	for _, m := range s.moves {
+		rows += m.rows
+	}
+	return float64(rows) / speedWindow.Seconds()
+}
+
+// lookahead returns how many pages to keep loaded beyond the screen: enough
+// to last lookaheadHorizon at the current scroll speed, at least one and
+// PrefetchPages, at most MaxPrefetchPages. Slow browsing so stays a page
+// ahead while fast flicking fetches further.
+func (g *Grid) lookahead(perPage int) int {
+	rows := g.scroll.rowsPerSecond() * lookaheadHorizon.Seconds()
+	pages := int(rows*float64(g.cols)/float64(perPage)) + 1
+	most := g.maxPrefetch
+	if most <= 0 {
+		most = defaultMaxPrefetch
+	}
+	return min(max(pages, g.prefetch, 1), max(most, g.prefetch))
+}
+
+// applyLoaded appends a fetched page, or holds it until the pages before it
+// have arrived, since concurrent fetches can finish out of order.
+func (g *Grid) applyLoaded(result loadResult) {
+	g.pending[result.page] = result
+	for {
+		r, ok := g.pending[g.nextPage]
+		if !ok {
+			return
+		}
+		delete(g.pending, g.nextPage)
+		if r.meta.Total > 0 {
+			g.meta = r.meta
+		}
+		if r.err == nil && r.meta.LastPage > 0 {
+			g.lastPage = r.meta.LastPage
+		}
+		g.appendLoaded(r.wallpapers, r.thumbPaths)
+		g.nextPage = r.nextPage
+		if r.err != nil {
+			g.status = fmt.Sprintf(i18n.T("Loading failed: %v"), r.err)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
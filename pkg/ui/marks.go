@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// handleMarkKey finishes an 'm' or apostrophe sequence started by the
+// previous key: a digit 1-9 sets or jumps to that mark, anything else
+// cancels. An 'r' sequence takes a rating instead.
+func (g *Grid) handleMarkKey(key []byte) {
+	prefix := g.markPrefix
+	g.markPrefix = 0
+	if prefix == 'r' {
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '0'+store.MaxStars && len(g.wallpapers) > 0 {
+			g.rateSelected(int(key[0] - '0'))
+		}
+		return
+	}
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' || len(g.wallpapers) == 0 {
+		return
+	}
+	n := key[0]
+	if prefix == 'm' {
+		g.marks[n] = g.wallpapers[g.selected].ID
+		g.status = fmt.Sprintf(i18n.T("Mark %c set"), n)
+		g.prevSelected = -1 // repaint so the label shows the mark
+		return
+	}
+	g.jumpToMark(n)
+}
+
+// jumpToMark selects the wallpaper holding mark n. Marks follow wallpapers
+// by ID, so they survive page loads and deletions before them; a mark
+// hidden by the filter clears it.
+func (g *Grid) jumpToMark(n byte) {
+	id, ok := g.marks[n]
+	if !ok {
+		g.status = fmt.Sprintf(i18n.T("Mark %c is not set"), n)
+		return
+	}
+	idx := g.indexOf(id)
+	if idx < 0 && g.filter != nil {
+		g.clearFilter()
+		idx = g.indexOf(id)
+	}
+	if idx < 0 {
+		g.status = fmt.Sprintf(i18n.T("Mark %c is not in these results"), n)
+		return
+	}
+	g.selected = idx
+	g.ensureVisible()
+}
+
+func (g *Grid) indexOf(id string) int {
+	for i, wp := range g.wallpapers {
+		if wp.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// markLabel lists the marks held by wp, e.g. "[1,4]", or "" for none.
+func (g *Grid) markLabel(wp provider.Wallpaper) string {
+	var nums []string
+	for n := byte('1'); n <= '9'; n++ {
+		if id, ok := g.marks[n]; ok && id == wp.ID {
+			nums = append(nums, string(n))
+		}
+	}
+	if len(nums) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(nums, ",") + "]"
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+	"github.com/davenicholson-xyz/vista/pkg/renderer"
+)
+
+const defaultSlideInterval = 5 * time.Second
+
+// toggleSlideshow starts or stops the auto-advancing fullscreen preview.
+func (g *Grid) toggleSlideshow() {
+	g.slideshow = !g.slideshow
+	g.prevSelected = -1 // force a full repaint of whichever view is next
+}
+
+// advanceSlideshow steps to the next wallpaper, wrapping to the start once
+// every page has been loaded.
+func (g *Grid) advanceSlideshow() {
+	if len(g.wallpapers) == 0 {
+		return
+	}
+	if g.selected < len(g.wallpapers)-1 {
+		g.selected++
+	} else if g.provider == nil || g.nextPage > g.lastPage {
+		g.selected = 0
+	}
+	g.ensureVisible()
+}
+
+// writePreviewTo renders the selected wallpaper across the whole terminal,
+// leaving the bottom row for the status line.
+func (g *Grid) writePreviewTo(b *strings.Builder) {
+	w, h := g.termSize()
+	ph := h - statusHeight
+	if ph < minCellHeight {
+		ph = minCellHeight
+	}
+
+	g.clearPlacements(b)
+	b.WriteString("\033[H\033[2J")
+	if g.selected >= len(g.wallpapers) {
+		return
+	}
+	thumbPath := g.thumbPaths[g.selected]
+	if s := g.streamed; s.path != "" && s.id == g.wallpapers[g.selected].ID {
+		thumbPath = s.path
+	}
+	if p, ok := g.renderer.(renderer.Placer); ok && thumbPath != "" {
+		p.Place("preview", thumbPath, 0, 0, w, ph) //nolint:errcheck
+		return
+	}
+	out := g.placeholderLines(w, ph)
+	for _, path := range []string{thumbPath, g.thumbPaths[g.selected]} {
+		if path == "" {
+			continue
+		}
+		if rendered, err := g.renderer.Render(path, w, ph); err == nil {
+			out = rendered
+			break
+		}
+	}
+	for i, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		fmt.Fprintf(b, "\033[%d;1H%s", i+1, line)
+	}
+}
+
+func (g *Grid) slideshowStatus() string {
+	wp := g.wallpapers[g.selected]
+	return fmt.Sprintf(i18n.T("slideshow %d/%d  %s  %s  (p/esc to stop)"),
+		g.selected+1, len(g.wallpapers), wp.ID, wp.Resolution)
+}
+
+// streamed is the sharpest copy yet of a wallpaper downloading to be set:
+// the completed scans of a progressive JPEG, then the finished file. The
+// preview shows it in place of the thumbnail.
+type streamed struct {
+	id   string
+	path string
+}
+
+// streamScans returns ctx set up to copy each completed scan of wp's
+// download into the temp directory for the preview, counting them in n.
+func (g *Grid) streamScans(ctx context.Context, wp provider.Wallpaper, n *int) context.Context {
+	return wallpaper.WithScans(ctx, func(partial string) {
+		*n++
+		dst := filepath.Join(g.tempDir, fmt.Sprintf("scan-%s-%d.jpg", wp.ID, *n))
+		if err := copyFile(partial, dst); err != nil {
+			g.logger.Printf("preview scan %s: %v", wp.ID, err)
+			return
+		}
+		select {
+		case g.streamCh <- streamed{id: wp.ID, path: dst}:
+		default:
+			os.Remove(dst) // the preview is behind; a later scan will do
+		}
+	})
+}
+
+// sendStreamed hands s to the Run loop, waiting unless the grid has closed.
+func (g *Grid) sendStreamed(s streamed) {
+	select {
+	case g.streamCh <- s:
+	case <-g.ctx.Done():
+	}
+}
+
+// applyStreamed keeps s, discarding the scan it replaces, and repaints the
+// preview if it shows that wallpaper.
+func (g *Grid) applyStreamed(s streamed) {
+	if old := g.streamed.path; old != "" && filepath.Dir(old) == g.tempDir {
+		os.Remove(old)
+	}
+	g.streamed = s
+	if g.slideshow && g.selected < len(g.wallpapers) && g.wallpapers[g.selected].ID == s.id {
+		g.prevSelected = -1
+	}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressWidth is the width of a progress bar between its brackets.
+const progressWidth = 20
+
+// ProgressBar draws a download's progress for the status line or a
+// terminal, e.g. "[=========>          ]  48%  3.6 MB / 7.5 MB". Without
+// a total it shows only the size so far.
+func ProgressBar(done, total int64) string {
+	if total <= 0 {
+		return humanSizeOrZero(done)
+	}
+	done = min(done, total)
+	filled := int(done * progressWidth / total)
+	bar := strings.Repeat("=", filled)
+	if filled < progressWidth {
+		bar += ">" + strings.Repeat(" ", progressWidth-filled-1)
+	}
+	return fmt.Sprintf("[%s] %3d%%  %s / %s", bar, done*100/total, humanSizeOrZero(done), humanSize(total))
+}
+
+// PrintProgress returns a download progress callback, see
+// wallpaper.WithProgress, that redraws label and a progress bar on one
+// terminal line of w and ends the line when the download completes.
+func PrintProgress(w io.Writer, label string) func(done, total int64) {
+	return func(done, total int64) {
+		fmt.Fprintf(w, "\r\033[2K%s  %s", label, ProgressBar(done, total))
+		if done == total {
+			fmt.Fprintln(w)
+		}
+	}
+}
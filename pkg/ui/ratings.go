@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/store"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// rateSelected gives the selected wallpaper stars, 0 clearing its rating.
+func (g *Grid) rateSelected(stars int) {
+	wp := g.wallpapers[g.selected]
+	if err := g.ratings.Rate(store.RatingKey(wp), stars); err != nil {
+		g.status = fmt.Sprintf(i18n.T("Saving ratings failed: %v"), err)
+		return
+	}
+	if stars == 0 {
+		g.status = fmt.Sprintf(i18n.T("Cleared the rating of %s"), wp.ID)
+	} else {
+		g.status = fmt.Sprintf(i18n.T("Rated %s %d/%d"), wp.ID, stars, store.MaxStars)
+	}
+	g.prevSelected = -1 // repaint so the label shows the rating
+}
+
+// noteSelected replaces the note on the selected wallpaper; an empty one
+// removes it.
+func (g *Grid) noteSelected(note string) {
+	if len(g.wallpapers) == 0 {
+		return
+	}
+	wp := g.wallpapers[g.selected]
+	note = strings.TrimSpace(note)
+	if err := g.ratings.SetNote(store.RatingKey(wp), note); err != nil {
+		g.status = fmt.Sprintf(i18n.T("Saving ratings failed: %v"), err)
+		return
+	}
+	if note == "" {
+		g.status = fmt.Sprintf(i18n.T("Removed the note on %s"), wp.ID)
+	} else {
+		g.status = fmt.Sprintf(i18n.T("Noted %s"), wp.ID)
+	}
+}
+
+// ratingLabel is wp's rating as "4/5", or "" when it is unrated.
+func (g *Grid) ratingLabel(wp provider.Wallpaper) string {
+	if g.ratings == nil {
+		return ""
+	}
+	r := g.ratings.Get(store.RatingKey(wp))
+	if r.Stars == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", r.Stars, store.MaxStars)
+}
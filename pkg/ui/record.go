@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// recorder writes a grid session as an asciinema v2 cast: a JSON header
+// line, then one [seconds, kind, data] array per event. Kind is "o" for
+// terminal output, "i" for key input and "r" for a resize. A nil recorder
+// records nothing.
+type recorder struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	start time.Time
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+func newRecorder(w io.Writer, width, height int) *recorder {
+	r := &recorder{enc: json.NewEncoder(w), start: time.Now()}
+	r.enc.Encode(castHeader{ //nolint:errcheck
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Env:       map[string]string{"TERM": os.Getenv("TERM"), "TERM_PROGRAM": os.Getenv("TERM_PROGRAM")},
+	})
+	return r
+}
+
+func (r *recorder) event(kind, data string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enc.Encode([]any{time.Since(r.start).Seconds(), kind, data}) //nolint:errcheck
+}
+
+// print writes s to the terminal, recording it as output.
+func (g *Grid) print(s string) {
+	if g.out != nil {
+		fmt.Fprint(g.out, s)
+	} else {
+		fmt.Print(s)
+	}
+	g.rec.event("o", s)
+}
+
+// Replay plays the output of an asciinema v2 cast back to w at its recorded
+// speed, e.g. a session saved with --record. Input and resize events are
+// skipped; play it in a terminal of the recorded size.
+func Replay(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20) // kitty frames are large
+	if !sc.Scan() {
+		return fmt.Errorf("empty cast")
+	}
+	var h castHeader
+	if err := json.Unmarshal(sc.Bytes(), &h); err != nil || h.Version != 2 {
+		return fmt.Errorf("not an asciinema v2 cast")
+	}
+
+	start := time.Now()
+	for sc.Scan() {
+		var ev [3]any
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			return fmt.Errorf("bad cast event: %w", err)
+		}
+		at, _ := ev[0].(float64)
+		kind, _ := ev[1].(string)
+		data, _ := ev[2].(string)
+		if kind != "o" {
+			continue
+		}
+		time.Sleep(time.Until(start.Add(time.Duration(at * float64(time.Second)))))
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package ui
+
+import "os"
+
+// notifyResize is a no-op where there is no SIGWINCH; the layout is only
+// computed at startup.
+func notifyResize(chan<- os.Signal) {}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+)
+
+const (
+	// thumbRetries is how many more times a failed thumbnail is fetched
+	// before its cell is left as a placeholder.
+	thumbRetries = 4
+	// thumbRetryDelay is the wait before the first retry; it doubles after
+	// each failure.
+	thumbRetryDelay = 2 * time.Second
+	// thumbRetryWorkers bounds concurrent retries so a dropped connection
+	// doesn't turn a page of failures into a burst of requests.
+	thumbRetryWorkers = 2
+)
+
+// thumbResult is a thumbnail that downloaded on a retry.
+type thumbResult struct {
+	url  string
+	path string
+}
+
+// retryThumbs schedules another go at every download that failed: urls
+// whose entry in paths came back empty.
+func (g *Grid) retryThumbs(urls, paths []string) {
+	for i, u := range urls {
+		if u != "" && paths[i] == "" {
+			go g.retryThumb(u)
+		}
+	}
+}
+
+// retryThumb fetches url with exponential backoff, reporting a success to
+// the Run loop. It gives up once the grid quits.
+func (g *Grid) retryThumb(url string) {
+	delay := thumbRetryDelay
+	for attempt := 1; attempt <= thumbRetries; attempt++ {
+		select {
+		case <-time.After(delay):
+		case <-g.ctx.Done():
+			return
+		}
+		delay *= 2
+		g.retrySem <- struct{}{}
+		p, err := wallpaper.DownloadContext(g.ctx, url, g.tempDir, "")
+		<-g.retrySem
+		if err == nil {
+			g.thumbCh <- thumbResult{url: url, path: p}
+			return
+		}
+		g.logger.Printf("thumbnail %s (attempt %d/%d): %v", url, attempt, thumbRetries, err)
+	}
+}
+
+// applyThumb fills in every placeholder showing url and queues those cells
+// for repainting. Results that no longer match a loaded wallpaper, e.g.
+// after a search replaced them, are dropped.
+func (g *Grid) applyThumb(res thumbResult) {
+	for i, wp := range g.wallpapers {
+		if g.thumbPaths[i] == "" && g.thumbURL(wp) == res.url {
+			g.thumbPaths[i] = res.path
+			g.refreshed = append(g.refreshed, i)
+		}
+	}
+	if f := g.filter; f != nil {
+		for i, wp := range f.wallpapers {
+			if f.thumbPaths[i] == "" && g.thumbURL(wp) == res.url {
+				f.thumbPaths[i] = res.path
+			}
+		}
+	}
+}
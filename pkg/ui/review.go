@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// ReviewSummary is the outcome of a review session: how many wallpapers
+// were kept and deleted, and the bytes the deletions freed.
+type ReviewSummary struct {
+	Kept    int
+	Deleted int
+	Freed   int64
+}
+
+// reviewState tracks a review session, see Options.Review.
+type reviewState struct {
+	kept  map[string]bool // paths kept with space
+	sizes map[string]int64
+	sum   ReviewSummary
+}
+
+func newReviewState() *reviewState {
+	return &reviewState{kept: make(map[string]bool), sizes: make(map[string]int64)}
+}
+
+// Review returns the tally of a review session so far.
+func (g *Grid) Review() ReviewSummary {
+	if g.review == nil {
+		return ReviewSummary{}
+	}
+	return g.review.sum
+}
+
+// size returns the file size of wp, looked up once.
+func (r *reviewState) size(wp provider.Wallpaper) int64 {
+	n, ok := r.sizes[wp.Path]
+	if !ok {
+		if fi, err := os.Stat(wp.Path); err == nil {
+			n = fi.Size()
+		}
+		r.sizes[wp.Path] = n
+	}
+	return n
+}
+
+// keepSelected marks the selection as kept and moves on to the next one.
+func (g *Grid) keepSelected() {
+	wp := g.wallpapers[g.selected]
+	if !g.review.kept[wp.Path] {
+		g.review.kept[wp.Path] = true
+		g.review.sum.Kept++
+	}
+	g.prevSelected = -1 // repaint so the label mark updates
+	g.reviewNext()
+}
+
+// reviewDeleted counts wp, about to be deleted, towards the summary.
+func (g *Grid) reviewDeleted(wp provider.Wallpaper) {
+	r := g.review
+	if r.kept[wp.Path] {
+		delete(r.kept, wp.Path)
+		r.sum.Kept--
+	}
+	r.sum.Deleted++
+	r.sum.Freed += r.size(wp)
+}
+
+// reviewNext moves to the next wallpaper not yet kept, or reports that
+// every one has been decided.
+func (g *Grid) reviewNext() {
+	for i := g.selected + 1; i < len(g.wallpapers); i++ {
+		if !g.review.kept[g.wallpapers[i].Path] {
+			g.selectIndex(i)
+			return
+		}
+	}
+	g.status = i18n.T("All reviewed - q to finish")
+}
+
+// reviewStatus summarises the session so far.
+func (g *Grid) reviewStatus() string {
+	s := g.review.sum
+	return fmt.Sprintf(i18n.T("%d of %d left  kept %d  deleted %d (%s)  space keeps, d deletes"),
+		len(g.wallpapers)-len(g.review.kept), len(g.wallpapers), s.Kept, s.Deleted, humanSizeOrZero(s.Freed))
+}
+
+// humanSizeOrZero is humanSize with "0 KB" for nothing.
+func humanSizeOrZero(n int64) string {
+	if n <= 0 {
+		return "0 KB"
+	}
+	return humanSize(n)
+}
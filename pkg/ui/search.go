@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// Query is a search the grid can run or re-run with changed settings
+// through Options.Search. Purity and Categories are Wallhaven's 3-bit
+// strings, e.g. "110"; empty means the source's default.
+type Query struct {
+	Text       string
+	Sorting    string
+	Purity     string
+	Categories string
+}
+
+// sortings is the order 'S' steps through.
+var sortings = []string{"relevance", "toplist", "hot", "date_added", "random"}
+
+var (
+	purityNames   = [3]string{"sfw", "sketchy", "nsfw"}
+	categoryNames = [3]string{"general", "anime", "people"}
+)
+
+// searchResult is the first page of a search started from inside the grid.
+type searchResult struct {
+	gen        int // matches Grid.searchGen unless a newer search started
+	query      Query
+	provider   provider.Provider
+	wallpapers []provider.Wallpaper
+	thumbPaths []string
+	meta       provider.Meta
+	err        error
+	done       string // status once the results are shown
+	none       string // status when there are no results
+	refresh    bool   // replaces a saved first screen; see Refresh
+}
+
+// runSearch fetches the first page of q in the background; applySearch
+// swaps it in when it arrives.
+func (g *Grid) runSearch(q Query, done, none string) {
+	g.startSearch(q, g.search(q), done, none, false)
+}
+
+// startSearch is runSearch for the results of provider p.
+func (g *Grid) startSearch(q Query, p provider.Provider, done, none string, refresh bool) {
+	g.searchGen++
+	gen := g.searchGen
+	go func() {
+		res := searchResult{gen: gen, query: q, provider: p, done: done, none: none, refresh: refresh}
+		res.wallpapers, res.meta, res.err = g.providerPage(res.provider, 1)
+		urls := make([]string, len(res.wallpapers))
+		for i, wp := range res.wallpapers {
+			urls[i] = g.thumbURL(wp)
+		}
+		res.thumbPaths = wallpaper.DownloadAllContext(g.ctx, urls, g.tempDir, g.workers)
+		g.retryThumbs(urls, res.thumbPaths)
+		g.searchCh <- res
+	}()
+}
+
+// applySearch swaps the grid over to a finished search. Pages still loading
+// for the old results are discarded when they arrive, as is a search
+// overtaken by a newer one.
+func (g *Grid) applySearch(res searchResult) {
+	switch {
+	case res.gen != g.searchGen:
+		return
+	case res.err != nil:
+		g.logger.Printf("search %q: %v", res.query.Text, res.err)
+		g.status = fmt.Sprintf(i18n.T("Search failed: %v"), res.err)
+		return
+	case len(res.wallpapers) == 0:
+		g.status = res.none
+		return
+	}
+	g.clearFilter()
+	g.gen++
+	g.query = &res.query
+	g.provider = res.provider
+	g.wallpapers = res.wallpapers
+	g.thumbPaths = res.thumbPaths
+	g.rendered = make(map[int]string)
+	if res.refresh {
+		// Fresh results for the saved screen; stay where the user is.
+		g.selected = min(g.selected, len(g.wallpapers)-1)
+		go g.saveFirstScreen(res.meta, res.wallpapers, slices.Clone(res.thumbPaths))
+	} else {
+		g.selected = 0
+		g.scrollRow = 0
+	}
+	g.nextPage = 2
+	g.requested = 2
+	clear(g.pending)
+	g.lastPage = res.meta.LastPage
+	g.meta = res.meta
+	g.prevSelected = -1
+	g.status = res.done
+}
+
+// toggleFilter flips bit i of the purity (purity true) or categories of the
+// current search and runs it again. At least one bit has to stay on.
+func (g *Grid) toggleFilter(purity bool, i int) {
+	switch {
+	case g.search == nil || g.query == nil:
+		g.status = i18n.T("Filters only apply to search results")
+		return
+	case purity && g.purityLocked:
+		g.status = i18n.T("purity is locked to sfw")
+		return
+	}
+	q := *g.query
+	bits := &q.Categories
+	if purity {
+		bits = &q.Purity
+	}
+	b := []byte(*bits)
+	if len(b) != 3 {
+		b = []byte("100")
+		if !purity {
+			b = []byte("111")
+		}
+	}
+	if b[i] == '1' {
+		b[i] = '0'
+	} else {
+		b[i] = '1'
+	}
+	if string(b) == "000" {
+		g.status = i18n.T("At least one must stay selected")
+		return
+	}
+	*bits = string(b)
+
+	g.query = &q // further toggles build on this one
+	desc := filterDesc(q)
+	g.status = fmt.Sprintf(i18n.T("Searching with %s..."), desc)
+	g.runSearch(q, desc, fmt.Sprintf(i18n.T("No wallpapers match %s"), desc))
+}
+
+// cycleSorting re-runs the current search with the next sorting in
+// sortings, from page 1.
+func (g *Grid) cycleSorting() {
+	if g.search == nil || g.query == nil {
+		g.status = i18n.T("Sorting only applies to search results")
+		return
+	}
+	q := *g.query
+	next := 0
+	for i, s := range sortings {
+		if s == q.Sorting {
+			next = (i + 1) % len(sortings)
+		}
+	}
+	q.Sorting = sortings[next]
+
+	g.query = &q
+	g.status = fmt.Sprintf(i18n.T("Sorting by %s..."), q.Sorting)
+	g.runSearch(q, fmt.Sprintf(i18n.T("Sorted by %s"), q.Sorting), i18n.T("No results found."))
+}
+
+// filterDesc summarises a query's purity and categories, e.g.
+// "sfw+sketchy / general+anime".
+func filterDesc(q Query) string {
+	names := func(bits string, all [3]string) string {
+		var on []string
+		for i, name := range all {
+			if i < len(bits) && bits[i] == '1' {
+				on = append(on, name)
+			}
+		}
+		return strings.Join(on, "+")
+	}
+	return names(q.Purity, purityNames) + " / " + names(q.Categories, categoryNames)
+}
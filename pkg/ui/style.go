@@ -0,0 +1,65 @@
+package ui
+
+// borderStyle holds the characters used to draw a cell frame.
+type borderStyle struct {
+	tl, tr, bl, br, h, v string
+}
+
+var borderStyles = map[string]borderStyle{
+	"double":  {"╔", "╗", "╚", "╝", "═", "║"},
+	"single":  {"┌", "┐", "└", "┘", "─", "│"},
+	"rounded": {"╭", "╮", "╰", "╯", "─", "│"},
+	"heavy":   {"┏", "┓", "┗", "┛", "━", "┃"},
+	"ascii":   {"+", "+", "+", "+", "-", "|"},
+}
+
+const defaultBorderStyle = "double"
+
+// BorderStyles lists the accepted Options.BorderStyle values.
+func BorderStyles() []string {
+	return []string{"double", "single", "rounded", "heavy", "ascii"}
+}
+
+// Selection indicator styles, see Options.Selection. All but the default
+// mark the selected cell without relying on colour.
+const (
+	selectColor   = "color"   // cyan frame (default)
+	selectInverse = "inverse" // frame and label in reverse video
+	selectThick   = "thick"   // solid block frame
+	selectArrow   = "arrow"   // markers either side of the label
+)
+
+// SelectionStyles lists the accepted Options.Selection values.
+func SelectionStyles() []string {
+	return []string{selectColor, selectInverse, selectThick, selectArrow}
+}
+
+// thickBorders frame the selected cell for the thick selection style.
+var (
+	thickBorder      = borderStyle{"█", "█", "█", "█", "█", "█"}
+	thickASCIIBorder = borderStyle{"#", "#", "#", "#", "#", "#"}
+)
+
+// fillGlyphs are the characters used for image placeholders.
+type fillGlyphs struct {
+	pending string // thumbnail not downloaded yet
+	failed  string // renderer rejected the thumbnail
+	star    string // favorite marker in the cell label
+	check   string // kept marker in review labels
+	arrowL  string // selection markers for the arrow style
+	arrowR  string
+	// Page strip segments: loaded, not loaded yet, and the page on screen.
+	pageDone, pageTodo, pageHere string
+}
+
+var (
+	unicodeFill = fillGlyphs{pending: "░", failed: "╱", star: "★", check: "✓", arrowL: "▶", arrowR: "◀", pageDone: "▰", pageTodo: "▱", pageHere: "▮"}
+	asciiFill   = fillGlyphs{pending: ".", failed: "/", star: "*", check: "+", arrowL: ">", arrowR: "<", pageDone: "=", pageTodo: "-", pageHere: "|"}
+)
+
+const (
+	selectedColor   = "\033[1;96m" // bright cyan
+	unselectedColor = "\033[90m"   // dim grey
+	resetColor      = "\033[0m"
+	inverseVideo    = "\033[7m"
+)
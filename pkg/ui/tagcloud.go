@@ -0,0 +1,363 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// tagFetchWorkers bounds concurrent detail lookups for the tag cloud, which
+// keeps a full page of results inside Wallhaven's rate limit for longer.
+const tagFetchWorkers = 2
+
+// tagCloudState is the 'c' screen: every tag of the loaded results, most
+// frequent first.
+type tagCloudState struct {
+	gen      int // matches tagResult.gen for lookups started by this screen
+	counts   map[string]*tagCount
+	tags     []*tagCount // sorted by count, then name
+	selected int
+	scroll   int // first visible line
+	total    int // wallpapers aggregated
+	pending  int // tag lookups still running
+	failed   int
+	dirty    bool
+}
+
+type tagCount struct {
+	tag provider.Tag
+	n   int
+}
+
+// tagResult carries one wallpaper's tags back to the Run loop.
+type tagResult struct {
+	gen  int
+	id   string
+	tags []provider.Tag
+	err  error
+}
+
+// openTagCloud aggregates tags across every loaded wallpaper, including those
+// hidden by a filter. Search results carry no tags, so any not seen before
+// are looked up through the Detailer in the background.
+func (g *Grid) openTagCloud() {
+	all := g.wallpapers
+	if g.filter != nil {
+		all = g.filter.wallpapers
+	}
+	g.cloudGen++
+	c := &tagCloudState{gen: g.cloudGen, counts: make(map[string]*tagCount), total: len(all), dirty: true}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, wp := range all {
+		if seen[wp.ID] {
+			continue
+		}
+		seen[wp.ID] = true
+		tags, ok := g.tagCache[wp.ID]
+		if len(wp.Tags) > 0 {
+			tags, ok = wp.Tags, true
+		}
+		if ok {
+			c.add(tags)
+		} else if g.detailer != nil && wp.ID != "" {
+			missing = append(missing, wp.ID)
+		}
+	}
+	c.pending = len(missing)
+	c.sort()
+	g.cloud = c
+	if len(missing) > 0 {
+		go g.fetchTags(c.gen, missing)
+	}
+}
+
+// fetchTags looks up the tags of each ID, a few at a time.
+func (g *Grid) fetchTags(gen int, ids []string) {
+	sem := make(chan struct{}, tagFetchWorkers)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d, err := g.lookupDetails(id)
+			g.tagCh <- tagResult{gen: gen, id: id, tags: d.Tags, err: err}
+		}()
+	}
+	wg.Wait()
+}
+
+// applyTags caches a finished lookup and adds it to the cloud it was started
+// for. Fetched tags are also stored on the wallpapers so Ctrl-f matches them.
+func (g *Grid) applyTags(res tagResult) {
+	if res.err != nil {
+		g.logger.Printf("tags %s: %v", res.id, res.err)
+	} else {
+		g.tagCache[res.id] = res.tags
+		g.storeTags(res.id, res.tags)
+	}
+	c := g.cloud
+	if c == nil || c.gen != res.gen {
+		return
+	}
+	c.pending--
+	if res.err != nil {
+		c.failed++
+	} else {
+		var current string
+		if c.selected < len(c.tags) {
+			current = c.tags[c.selected].tag.Name
+		}
+		c.add(res.tags)
+		c.sort()
+		for i, t := range c.tags {
+			if t.tag.Name == current {
+				c.selected = i
+			}
+		}
+	}
+	c.dirty = true
+}
+
+// storeTags records tags on every loaded copy of wallpaper id.
+func (g *Grid) storeTags(id string, tags []provider.Tag) {
+	lists := [][]provider.Wallpaper{g.wallpapers}
+	if g.filter != nil {
+		lists = append(lists, g.filter.wallpapers)
+	}
+	for _, list := range lists {
+		for i := range list {
+			if list[i].ID == id {
+				list[i].Tags = tags
+			}
+		}
+	}
+}
+
+func (c *tagCloudState) add(tags []provider.Tag) {
+	for _, t := range tags {
+		key := strings.ToLower(t.Name)
+		if tc, ok := c.counts[key]; ok {
+			tc.n++
+			continue
+		}
+		tc := &tagCount{tag: t, n: 1}
+		c.counts[key] = tc
+		c.tags = append(c.tags, tc)
+	}
+}
+
+func (c *tagCloudState) sort() {
+	sort.Slice(c.tags, func(i, j int) bool {
+		if c.tags[i].n != c.tags[j].n {
+			return c.tags[i].n > c.tags[j].n
+		}
+		return c.tags[i].tag.Name < c.tags[j].tag.Name
+	})
+}
+
+// closeTagCloud returns to the grid.
+func (g *Grid) closeTagCloud() {
+	g.cloud = nil
+	g.prevSelected = -1 // force full redraw
+}
+
+// tagPos is where a tag sits in the cloud layout.
+type tagPos struct {
+	line, col, width int
+}
+
+// tagLabel is a tag as shown in the cloud, with its count.
+func tagLabel(t *tagCount) string {
+	return fmt.Sprintf("%s (%d)", t.tag.Name, t.n)
+}
+
+// cloudLayout flows the tags into lines of at most width columns.
+func (c *tagCloudState) cloudLayout(width int) []tagPos {
+	pos := make([]tagPos, len(c.tags))
+	line, col := 0, 0
+	for i, t := range c.tags {
+		w := utf8.RuneCountInString(tagLabel(t))
+		if col > 0 && col+w > width {
+			line++
+			col = 0
+		}
+		pos[i] = tagPos{line: line, col: col, width: w}
+		col += w + 3
+	}
+	return pos
+}
+
+// handleTagCloudKey moves through the cloud. Left and right step through the
+// tags in order; up and down jump to the nearest tag on the adjacent line.
+func (g *Grid) handleTagCloudKey(action keyAction) {
+	c := g.cloud
+	switch action {
+	case actionTags, actionEscape:
+		g.closeTagCloud()
+		return
+	case actionSelect:
+		if len(c.tags) > 0 {
+			g.searchTag(c.tags[c.selected].tag)
+		}
+		return
+	}
+	if len(c.tags) == 0 {
+		return
+	}
+
+	w, _ := g.termSize()
+	pos := c.cloudLayout(w - 4)
+	prev := c.selected
+	switch action {
+	case actionLeft:
+		c.selected = max(c.selected-1, 0)
+	case actionRight:
+		c.selected = min(c.selected+1, len(c.tags)-1)
+	case actionUp, actionDown:
+		target := pos[c.selected].line - 1
+		if action == actionDown {
+			target = pos[c.selected].line + 1
+		}
+		mid := pos[c.selected].col + pos[c.selected].width/2
+		best, bestDist := -1, 0
+		for i, p := range pos {
+			if p.line != target {
+				continue
+			}
+			d := max(p.col-mid, mid-(p.col+p.width), 0)
+			if best < 0 || d < bestDist {
+				best, bestDist = i, d
+			}
+		}
+		if best >= 0 {
+			c.selected = best
+		}
+	}
+	if c.selected != prev {
+		c.dirty = true
+	}
+}
+
+// searchTag replaces the results with a search for t.
+func (g *Grid) searchTag(t provider.Tag) {
+	if g.search == nil {
+		g.status = i18n.T("Searching isn't available from this list")
+		return
+	}
+	query := t.Name
+	if t.ID > 0 {
+		query = fmt.Sprintf("id:%d", t.ID)
+	}
+	g.closeTagCloud()
+	g.status = fmt.Sprintf(i18n.T("Searching for tag %s..."), t.Name)
+	q := Query{Text: query, Sorting: "date_added"}
+	if g.query != nil {
+		q.Purity, q.Categories = g.query.Purity, g.query.Categories
+	}
+	g.runSearch(q, fmt.Sprintf(i18n.T("Tagged %s"), t.Name), fmt.Sprintf(i18n.T("No wallpapers tagged %s"), t.Name))
+}
+
+// searchUploader replaces the results with the uploads of a Wallhaven
+// user, newest first, from the detail screen.
+func (g *Grid) searchUploader(name string) {
+	if g.search == nil {
+		g.status = i18n.T("Searching isn't available from this list")
+		return
+	}
+	g.closeDetail()
+	g.status = fmt.Sprintf(i18n.T("Searching uploads by %s..."), name)
+	q := Query{Text: "@" + name, Sorting: "date_added"}
+	if g.query != nil {
+		q.Purity, q.Categories = g.query.Purity, g.query.Categories
+	}
+	g.runSearch(q, fmt.Sprintf(i18n.T("Uploaded by %s"), name), fmt.Sprintf(i18n.T("No uploads by %s"), name))
+}
+
+// searchSimilar replaces the results with wallpapers Wallhaven considers
+// similar to wp, via a like: query.
+func (g *Grid) searchSimilar(wp provider.Wallpaper) {
+	if g.search == nil || wp.ID == "" {
+		g.status = i18n.T("Searching isn't available from this list")
+		return
+	}
+	g.closeDetail()
+	g.status = fmt.Sprintf(i18n.T("Searching for wallpapers like %s..."), wp.ID)
+	q := Query{Text: "like:" + wp.ID, Sorting: "relevance"}
+	if g.query != nil {
+		q.Purity, q.Categories = g.query.Purity, g.query.Categories
+	}
+	g.runSearch(q, fmt.Sprintf(i18n.T("Similar to %s"), wp.ID), fmt.Sprintf(i18n.T("Nothing similar to %s found"), wp.ID))
+}
+
+// writeTagCloudTo draws the cloud full screen. Terminals have one font size,
+// so frequency is shown by weight instead: the most common tags are bold
+// and bright, one-off tags dim.
+func (g *Grid) writeTagCloudTo(b *strings.Builder) {
+	c := g.cloud
+	w, h := g.termSize()
+
+	g.clearPlacements(b)
+	b.WriteString("\033[H\033[2J")
+
+	title := fmt.Sprintf(i18n.T("Tags across %d wallpapers"), c.total)
+	if c.pending > 0 {
+		title += "  " + fmt.Sprintf(i18n.T("(fetching %d more)"), c.pending)
+	}
+	if c.failed > 0 {
+		title += "  " + fmt.Sprintf(i18n.T("(%d lookups failed)"), c.failed)
+	}
+	fmt.Fprintf(b, "\033[1;3H%s%s%s", selectedColor, title, resetColor)
+	if len(c.tags) == 0 {
+		msg := i18n.T("No tags yet")
+		if c.pending == 0 {
+			msg = i18n.T("No tags found")
+		}
+		fmt.Fprintf(b, "\033[3;3H%s", msg)
+		return
+	}
+
+	pos := c.cloudLayout(w - 4)
+	rows := max(h-statusHeight-3, 1)
+	line := pos[c.selected].line
+	if line < c.scroll {
+		c.scroll = line
+	} else if line >= c.scroll+rows {
+		c.scroll = line - rows + 1
+	}
+
+	top := c.tags[0].n
+	for i, t := range c.tags {
+		p := pos[i]
+		if p.line < c.scroll || p.line >= c.scroll+rows {
+			continue
+		}
+		style := tagStyle(t.n, top)
+		if i == c.selected {
+			style = "\033[7m" + style
+		}
+		fmt.Fprintf(b, "\033[%d;%dH%s%s%s", p.line-c.scroll+3, p.col+3, style, tagLabel(t), resetColor)
+	}
+}
+
+// tagStyle picks the emphasis for a tag seen n times, top being the most
+// frequent count.
+func tagStyle(n, top int) string {
+	switch {
+	case n*3 >= top*2 && n > 1:
+		return "\033[1;97m" // bold bright white
+	case n*3 >= top && n > 1:
+		return "\033[1;37m"
+	case n > 1:
+		return "\033[37m"
+	}
+	return unselectedColor
+}
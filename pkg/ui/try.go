@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davenicholson-xyz/vista/internal/i18n"
+	"github.com/davenicholson-xyz/vista/internal/wallpaper"
+	"github.com/davenicholson-xyz/vista/pkg/provider"
+)
+
+// defaultTryDuration is how long a 't' trial lasts before reverting.
+const defaultTryDuration = 10 * time.Second
+
+// tryState is a wallpaper set on trial with 't'. It reverts to previous at
+// deadline unless confirmed with Enter.
+type tryState struct {
+	wp       provider.Wallpaper
+	previous string // wallpaper to restore; "" if unknown
+	deadline time.Time
+}
+
+// startTry downloads and sets wp in the background, remembering the current
+// wallpaper so the trial can be undone. The trial starts once the set
+// succeeds, delivered on tryCh.
+func (g *Grid) startTry(wp provider.Wallpaper) {
+	previous := wallpaper.Current(g.setOpts.CurrentFile)
+	path, err := wallpaper.DownloadVerifiedContext(g.ctx, wp.Path, g.downloadDir, wallpaper.FileName(wp.Path, wp.Source, wp.ID), wp.Checksum)
+	if err == nil {
+		err = wallpaper.Set(path, setInfo(wp), g.setOpts)
+	}
+	if err != nil {
+		g.logger.Printf("try %s: %v", wp.ID, err)
+		g.notify(fmt.Sprintf(i18n.T("Failed to set %s: %v"), wp.ID, err))
+		return
+	}
+	g.tryCh <- tryState{wp: wp, previous: previous}
+}
+
+// endTry finishes the current trial, keeping the wallpaper or restoring the
+// previous one. Reverting runs in the background unless wait is set, which
+// quitting uses so the restore isn't cut short.
+func (g *Grid) endTry(keep, wait bool) {
+	t := g.trying
+	g.trying = nil
+	if t == nil {
+		return
+	}
+	if keep {
+		g.status = fmt.Sprintf(i18n.T("Kept %s"), t.wp.ID)
+		return
+	}
+	if t.previous == "" {
+		g.status = i18n.T("No previous wallpaper to revert to")
+		return
+	}
+	revert := func() {
+		if err := wallpaper.Set(t.previous, wallpaper.Info{}, g.setOpts); err != nil {
+			g.logger.Printf("revert to %s: %v", t.previous, err)
+			g.notify(fmt.Sprintf(i18n.T("Failed to revert: %v"), err))
+		}
+	}
+	if wait {
+		revert()
+		return
+	}
+	go revert()
+	g.status = i18n.T("Reverted to the previous wallpaper")
+}
+
+// tryStatus is the countdown shown in the status line during a trial.
+func (g *Grid) tryStatus() string {
+	left := max(int(time.Until(g.trying.deadline).Round(time.Second).Seconds()), 0)
+	return fmt.Sprintf(i18n.T("Trying %s - enter keeps it, esc reverts (%ds)"), g.trying.wp.ID, left)
+}